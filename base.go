@@ -0,0 +1,227 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"reflect"
+	"slices"
+	"time"
+	"unsafe"
+)
+
+// Base is an embeddable implementation of [Exception] for user-defined
+// exception types. A struct embedding [Base] directly satisfies [Exception]
+// through a pointer, and can carry any additional typed field:
+//
+//	const OrderError = exception.String("OrderError: order cannot be processed")
+//
+//	type OrderFailure struct {
+//	    exception.Base
+//	    OrderID string
+//	}
+//
+//	func NewOrderFailure(orderID string) *OrderFailure {
+//	    err := &OrderFailure{OrderID: orderID}
+//	    err.Init(err, OrderError)
+//	    return err
+//	}
+//
+// Methods that modify the exception never change the receiver. Instead, they
+// copy the whole embedding struct, modify the copy and return it, so the
+// returned [Exception] keeps the concrete user type and can be retrieved with
+// [errors.As]:
+//
+//	var failure *OrderFailure
+//	if errors.As(err, &failure) {
+//	    // use failure.OrderID
+//	}
+type Base struct {
+	self      Exception
+	exception fullException
+}
+
+// Init binds this [Base] to the struct embedding it and sets the type, the
+// message and the causes of the exception from the given source, usually a
+// [String] or the result of [Template.Format]. It must be called once, before
+// the exception is used, with a pointer to the struct embedding this [Base]
+// directly.
+func (b *Base) Init(self Exception, source Exception) {
+	value := reflect.ValueOf(self)
+	if value.Kind() != reflect.Pointer || baseField(value) != b {
+		panic("exception: Base.Init must be called with a pointer to the struct embedding this Base")
+	}
+	b.self = self
+	b.exception.Type = source.GetType()
+	b.exception.Message = source.GetMessage()
+	b.exception.Causes = slices.Clone(source.GetCause())
+}
+
+// baseField returns the [Base] embedded directly in the struct pointed to by
+// value, or nil if there is none.
+func baseField(value reflect.Value) *Base {
+	element := value.Elem()
+	if element.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < element.NumField(); i++ {
+		if field := element.Type().Field(i); field.Anonymous && field.Type == reflect.TypeFor[Base]() {
+			return element.Field(i).Addr().Interface().(*Base)
+		}
+	}
+	return nil
+}
+
+// clone copies the struct embedding this [Base] and returns the copy together
+// with its embedded [Base].
+func (b *Base) clone() (Exception, *Base) {
+	if b.self == nil {
+		copied := *b
+		return &copied, &copied
+	}
+	value := reflect.ValueOf(b.current())
+	copied := reflect.New(value.Elem().Type())
+	copied.Elem().Set(value.Elem())
+	self := copied.Interface().(Exception)
+	base := baseField(copied)
+	base.self = self
+	return self, base
+}
+
+// current returns the struct embedding this [Base], or this [Base] itself if
+// it was not bound. The struct is found from the address of this [Base] rather
+// than from the bound pointer, which still points to the original when the
+// struct was copied by value.
+func (b *Base) current() Exception {
+	if b.self == nil {
+		return b
+	}
+	structType := reflect.TypeOf(b.self).Elem()
+	// the field embedding a Base is named after it
+	field, _ := structType.FieldByName("Base")
+	return reflect.NewAt(structType, unsafe.Add(unsafe.Pointer(b), -int(field.Offset))).Interface().(Exception)
+}
+
+// Error returns a string representation of this exception in the form of "Type:
+// Message"
+func (b *Base) Error() string {
+	return b.exception.Error()
+}
+
+// GetType returns the type of this exception.
+func (b *Base) GetType() string {
+	return b.exception.Type
+}
+
+// GetMessage returns the message of this exception.
+func (b *Base) GetMessage() string {
+	return b.exception.Message
+}
+
+// SetMessage stores a message inside a copy of this exception.
+func (b *Base) SetMessage(message string, parameters ...any) Exception {
+	self, base := b.clone()
+	base.exception = b.exception.SetMessage(message, parameters...).(fullException)
+	return self
+}
+
+// GetCause returns the list of underlying causes associated with this exception.
+// The slice may be empty if no causes have been specified.
+func (b *Base) GetCause() []error {
+	return b.exception.Causes
+}
+
+// AddCause attaches one or more underlying causes to a copy of this exception.
+func (b *Base) AddCause(errors ...error) Exception {
+	self, base := b.clone()
+	base.exception = b.exception.AddCause(errors...).(fullException)
+	return self
+}
+
+// GetSuppressed returns the list of suppressed errors that were intentionally
+// ignored or deferred while handling this exception.
+func (b *Base) GetSuppressed() []error {
+	return b.exception.Suppressed
+}
+
+// AddSuppressed attaches one or more suppressed errors to a copy of this
+// exception.
+func (b *Base) AddSuppressed(errors ...error) Exception {
+	self, base := b.clone()
+	base.exception = b.exception.AddSuppressed(errors...).(fullException)
+	return self
+}
+
+// GetRecovered returns the value captured from a panic recovery, if any. It
+// returns nil if no value was recovered.
+func (b *Base) GetRecovered() any {
+	return b.exception.Recovered
+}
+
+// SetRecovered stores a recovered panic value inside a copy of this exception.
+func (b *Base) SetRecovered(recovered any) Exception {
+	self, base := b.clone()
+	base.exception = b.exception.SetRecovered(recovered).(fullException)
+	return self
+}
+
+// GetStackTrace returns the stack trace captured for this exception, represented
+// as [StackFrames]. The result may be nil if no stack trace was filled.
+func (b *Base) GetStackTrace() StackFrames {
+	return b.exception.Stack
+}
+
+// FillStackTrace captures the current call stack starting from the caller of
+// [Base.FillStackTrace] itself and attaches it to a copy of this exception.
+//
+// The skip parameter controls how many additional stack frames are omitted. A
+// value of 0 includes the caller of [Base.FillStackTrace], a value of 1 skips
+// that frame, and higher values skip more.
+func (b *Base) FillStackTrace(skip int) Exception {
+	return b.withStackTrace(StackTrace(skip + 1))
+}
+
+func (b *Base) withStackTrace(trace StackFrames) Exception {
+	self, base := b.clone()
+	base.exception = b.exception.withStackTrace(trace).(fullException)
+	return self
+}
+
+// GetID returns the unique instance ID of this exception, or an empty string if
+// no ID was assigned.
+func (b *Base) GetID() string {
+	return b.exception.ID
+}
+
+// GetTime returns the time this exception was created, or the zero time if no
+// time was assigned.
+func (b *Base) GetTime() time.Time {
+	return b.exception.Time
+}
+
+// GetGoroutines returns the snapshot of all goroutines captured when this
+// exception was recovered, or nil if there is none.
+func (b *Base) GetGoroutines() []Goroutine {
+	return b.exception.Goroutines
+}
+
+func (b *Base) __() {}
+
+// Unwrap returns the causes of this exception.
+func (b *Base) Unwrap() []error {
+	return b.exception.Causes
+}
+
+// Is reports whether the target is an [Exception] of the same type.
+func (b *Base) Is(target error) bool {
+	return is(b, target)
+}
+
+// As stores this exception in the target if it is a pointer to an [Exception]
+// of the same type.
+func (b *Base) As(target any) bool {
+	return as(b.current(), target)
+}
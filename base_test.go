@@ -0,0 +1,91 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+const OrderError = exception.String("OrderError: order cannot be processed")
+
+type OrderFailure struct {
+	exception.Base
+	OrderID string
+}
+
+func NewOrderFailure(orderID string) *OrderFailure {
+	err := &OrderFailure{OrderID: orderID}
+	err.Init(err, OrderError)
+	return err
+}
+
+func TestBaseCopyOnWrite(t *testing.T) {
+	original := NewOrderFailure("42")
+	modified := original.AddCause(errors.New("cause")).FillStackTrace(0)
+	if len(original.GetCause()) != 0 || original.GetStackTrace() != nil {
+		t.Errorf("Expected original exception to be unchanged")
+	}
+	failure, ok := modified.(*OrderFailure)
+	if !ok {
+		t.Fatalf("Expected modified exception to keep its type but got %T", modified)
+	}
+	if failure.OrderID != "42" || len(failure.GetCause()) != 1 {
+		t.Errorf("Expected modified exception to keep its fields but got %#v", failure)
+	}
+	checkStackTrace(t, failure.GetStackTrace(), "/go-exception_test.TestBaseCopyOnWrite")
+	if failure.Error() != "OrderError: order cannot be processed" {
+		t.Errorf("Expected error string of OrderError but got \"%s\"", failure.Error())
+	}
+}
+
+func TestBaseErrorsAs(t *testing.T) {
+	err := exception.Join(errors.New("other"), NewOrderFailure("42").SetMessage("failed"))
+	var failure *OrderFailure
+	if !errors.As(err, &failure) || failure.OrderID != "42" {
+		t.Fatalf("Expected errors.As to find OrderFailure but got %#v", failure)
+	}
+	if !errors.Is(err, OrderError) {
+		t.Errorf("Expected errors.Is to match OrderError")
+	}
+	var target exception.Exception = OrderError
+	if !failure.As(&target) || target != exception.Exception(failure) {
+		t.Errorf("Expected As to store the OrderFailure but got %#v", target)
+	}
+}
+
+func TestBaseValueCopy(t *testing.T) {
+	original := NewOrderFailure("1")
+	copied := *original
+	copied.OrderID = "2"
+	modified, ok := copied.SetMessage("failed").(*OrderFailure)
+	if !ok || modified.OrderID != "2" || modified.GetMessage() != "failed" {
+		t.Errorf("Expected a modified copy of the copied struct but got %#v", modified)
+	}
+	var failure *OrderFailure
+	if !errors.As(exception.Join(&copied), &failure) || failure != &copied {
+		t.Errorf("Expected errors.As to find the copied struct but got %#v", failure)
+	}
+	if original.OrderID != "1" || original.GetMessage() != OrderError.GetMessage() {
+		t.Errorf("Expected original exception to be unchanged but got %#v", original)
+	}
+}
+
+func TestBaseSiblingCopies(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+	base := NewOrderFailure("42").AddCause(errors.New("cause"), errors.New("cause"), errors.New("cause"))
+	x := base.AddCause(first).AddSuppressed(first)
+	y := base.AddCause(second).AddSuppressed(second)
+	if causes := x.GetCause(); causes[len(causes)-1] != first || x.GetSuppressed()[0] != first {
+		t.Errorf("Expected the cause of the first copy to be kept but got %v", causes)
+	}
+	if causes := y.GetCause(); causes[len(causes)-1] != second || y.GetSuppressed()[0] != second {
+		t.Errorf("Expected the cause of the second copy but got %v", causes)
+	}
+}
@@ -0,0 +1,598 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// BinaryError is the type of the errors returned while encoding or decoding
+// the binary format of [EncodeBinary].
+const BinaryError = String("BinaryError")
+
+// binaryVersion is the first byte of the binary format, incremented on every
+// incompatible change.
+const binaryVersion = 1
+
+// tags of the encoded errors
+const (
+	binaryNil = iota
+	binaryString
+	binaryFull
+	binaryJoin
+	binaryForeign
+	binaryRegistered
+)
+
+// tags of the encoded recovered values
+const (
+	recoveredNil = iota
+	recoveredError
+	recoveredString
+)
+
+// binaryMaxDepth limits the nesting of the decoded errors.
+const binaryMaxDepth = 1000
+
+func init() {
+	gob.Register(String(""))
+	gob.Register(fullException{})
+	gob.Register(multipleErrors{})
+}
+
+// EncodeBinary encodes the error in a compact binary format, meant for queues
+// and buffers between processes rather than for humans. Unlike [WriteJSON],
+// the [RedactionPolicy] is not applied.
+//
+// Every string, such as the file and function names of the stack frames, is
+// written once and referenced afterward. A [String] and the result of [Join]
+// keep their kind. Other exceptions are encoded as their type, message,
+// causes, suppressed errors, recovered value, stack trace, ID, time and
+// goroutines, and their kind is kept only if it was registered with
+// [RegisterBinary]. An error from another library is reduced to its message
+// and its stack trace (see [StackTraceOf]). A recovered value that is not an
+// error is reduced to its string representation.
+func EncodeBinary(err error) ([]byte, error) {
+	encoder := newBinaryEncoder()
+	encoder.error(err)
+	return encoder.finish()
+}
+
+// DecodeBinary decodes an error encoded by [EncodeBinary]. The decoded
+// exceptions match the original ones with [errors.Is], except for errors from
+// other libraries, which are decoded as exceptions with an empty type. The
+// program counters of the stack frames are not kept, since they are only
+// meaningful in the process that captured them.
+func DecodeBinary(data []byte) (Exception, error) {
+	decoder, err := newBinaryDecoder(data)
+	if err != nil {
+		return nil, err
+	}
+	decoded := decoder.error()
+	return decoded, decoder.finish()
+}
+
+// MarshalBinary encodes this exception with [EncodeBinary], which also
+// allows [encoding/gob] to encode it.
+func (e fullException) MarshalBinary() ([]byte, error) {
+	return EncodeBinary(e)
+}
+
+// UnmarshalBinary decodes this exception with [DecodeBinary].
+func (e *fullException) UnmarshalBinary(data []byte) error {
+	decoded, err := DecodeBinary(data)
+	if err != nil {
+		return err
+	}
+	full, ok := decoded.(fullException)
+	if !ok {
+		return BinaryError.SetMessage("unexpected exception %T", decoded)
+	}
+	*e = full
+	return nil
+}
+
+// MarshalBinary encodes this exception with [EncodeBinary], which also
+// allows [encoding/gob] to encode it.
+func (e multipleErrors) MarshalBinary() ([]byte, error) {
+	return EncodeBinary(e)
+}
+
+// UnmarshalBinary decodes this exception with [DecodeBinary].
+func (e *multipleErrors) UnmarshalBinary(data []byte) error {
+	decoded, err := DecodeBinary(data)
+	if err != nil {
+		return err
+	}
+	multiple, ok := decoded.(multipleErrors)
+	if !ok {
+		return BinaryError.SetMessage("unexpected exception %T", decoded)
+	}
+	*e = multiple
+	return nil
+}
+
+// MarshalBinary encodes this stack trace in the binary format of
+// [EncodeBinary].
+func (s StackFrames) MarshalBinary() ([]byte, error) {
+	encoder := newBinaryEncoder()
+	encoder.stackTrace(s)
+	return encoder.finish()
+}
+
+// UnmarshalBinary decodes a stack trace encoded by [StackFrames.MarshalBinary].
+func (s *StackFrames) UnmarshalBinary(data []byte) error {
+	decoder, err := newBinaryDecoder(data)
+	if err != nil {
+		return err
+	}
+	trace := decoder.stackTrace()
+	if err := decoder.finish(); err != nil {
+		return err
+	}
+	*s = trace
+	return nil
+}
+
+// ========================================
+
+// binaryKind is the encoding of a user-defined exception kind.
+type binaryKind struct {
+	name   string
+	encode func(exception Exception) ([]byte, error)
+	decode func(source Exception, payload []byte) (Exception, error)
+}
+
+var binaryKinds = struct {
+	sync.RWMutex
+	byType map[reflect.Type]binaryKind
+	byName map[string]binaryKind
+}{byType: map[reflect.Type]binaryKind{}, byName: map[string]binaryKind{}}
+
+// RegisterBinary registers the binary encoding of the exceptions of type E,
+// such as a struct embedding [Base] or a [Typed] exception, under a name that
+// identifies the kind in the encoded data and must not change between
+// versions. It panics if the name or the type is registered twice.
+//
+// The encode function returns the payload of the exception, that is
+// everything but the fields common to every [Exception]. The decode function
+// builds the exception back from the payload, using the source the same way
+// as [Base.Init] or [TypedFrom]; the fields common to every [Exception] are
+// restored afterward. A nil exception returned by the decode function is
+// reported as an invalid payload. An exception of a kind unknown to the decoder is
+// decoded without its payload, as if its kind was never registered.
+//
+//	exception.RegisterBinary("orders.OrderFailure",
+//		func(err *OrderFailure) ([]byte, error) {
+//			return []byte(err.OrderID), nil
+//		},
+//		func(source exception.Exception, payload []byte) (*OrderFailure, error) {
+//			err := &OrderFailure{OrderID: string(payload)}
+//			err.Init(err, source)
+//			return err, nil
+//		})
+func RegisterBinary[E Exception](
+	name string,
+	encode func(exception E) ([]byte, error),
+	decode func(source Exception, payload []byte) (E, error),
+) {
+	kindType := reflect.TypeFor[E]()
+	binaryKinds.Lock()
+	defer binaryKinds.Unlock()
+	if _, ok := binaryKinds.byName[name]; ok {
+		panic("exception: binary kind registered twice: " + name)
+	}
+	if _, ok := binaryKinds.byType[kindType]; ok {
+		panic("exception: binary kind registered twice: " + kindType.String())
+	}
+	kind := binaryKind{
+		name: name,
+		encode: func(exception Exception) ([]byte, error) {
+			return encode(exception.(E))
+		},
+		decode: func(source Exception, payload []byte) (Exception, error) {
+			return decode(source, payload)
+		},
+	}
+	binaryKinds.byType[kindType] = kind
+	binaryKinds.byName[name] = kind
+}
+
+func lookupBinaryKind(exception Exception) (binaryKind, bool) {
+	binaryKinds.RLock()
+	defer binaryKinds.RUnlock()
+	kind, ok := binaryKinds.byType[reflect.TypeOf(exception)]
+	return kind, ok
+}
+
+func lookupBinaryName(name string) (binaryKind, bool) {
+	binaryKinds.RLock()
+	defer binaryKinds.RUnlock()
+	kind, ok := binaryKinds.byName[name]
+	return kind, ok
+}
+
+// ========================================
+
+type binaryEncoder struct {
+	buffer  []byte
+	strings map[string]uint64
+	err     error
+}
+
+func newBinaryEncoder() *binaryEncoder {
+	return &binaryEncoder{buffer: []byte{binaryVersion}, strings: map[string]uint64{}}
+}
+
+func (e *binaryEncoder) finish() ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.buffer, nil
+}
+
+func (e *binaryEncoder) uvarint(value uint64) {
+	e.buffer = binary.AppendUvarint(e.buffer, value)
+}
+
+func (e *binaryEncoder) varint(value int64) {
+	e.buffer = binary.AppendVarint(e.buffer, value)
+}
+
+func (e *binaryEncoder) bytes(value []byte) {
+	e.uvarint(uint64(len(value)))
+	e.buffer = append(e.buffer, value...)
+}
+
+// string writes the index of the string if it was written before, otherwise
+// a zero followed by the string itself.
+func (e *binaryEncoder) string(value string) {
+	if index, ok := e.strings[value]; ok {
+		e.uvarint(index)
+		return
+	}
+	e.strings[value] = uint64(len(e.strings)) + 1
+	e.uvarint(0)
+	e.uvarint(uint64(len(value)))
+	e.buffer = append(e.buffer, value...)
+}
+
+func (e *binaryEncoder) error(err error) {
+	switch err := err.(type) {
+	case nil:
+		e.uvarint(binaryNil)
+	case String:
+		e.uvarint(binaryString)
+		e.string(string(err))
+	case multipleErrors:
+		e.uvarint(binaryJoin)
+		e.errors(err)
+	case fullException:
+		e.uvarint(binaryFull)
+		e.exception(err)
+	case Exception:
+		kind, ok := lookupBinaryKind(err)
+		if !ok {
+			e.uvarint(binaryFull)
+			e.exception(err)
+			return
+		}
+		payload, encodeErr := kind.encode(err)
+		if encodeErr != nil && e.err == nil {
+			e.err = BinaryError.AddCause(encodeErr)
+		}
+		e.uvarint(binaryRegistered)
+		e.string(kind.name)
+		e.bytes(payload)
+		e.exception(err)
+	default:
+		e.uvarint(binaryForeign)
+		e.string(err.Error())
+		e.stackTrace(StackTraceOf(err))
+	}
+}
+
+func (e *binaryEncoder) errors(errors []error) {
+	e.uvarint(uint64(len(errors)))
+	for _, err := range errors {
+		e.error(err)
+	}
+}
+
+func (e *binaryEncoder) exception(exception Exception) {
+	e.string(exception.GetType())
+	e.string(exception.GetMessage())
+	e.errors(exception.GetCause())
+	e.errors(exception.GetSuppressed())
+	e.recovered(exception.GetRecovered())
+	e.stackTrace(exception.GetStackTrace())
+	e.string(exception.GetID())
+	e.time(exception.GetTime())
+	e.goroutines(exception.GetGoroutines())
+}
+
+func (e *binaryEncoder) recovered(recovered any) {
+	switch recovered := recovered.(type) {
+	case nil:
+		e.uvarint(recoveredNil)
+	case error:
+		e.uvarint(recoveredError)
+		e.error(recovered)
+	default:
+		e.uvarint(recoveredString)
+		e.string(fmt.Sprint(recovered))
+	}
+}
+
+func (e *binaryEncoder) time(value time.Time) {
+	if value.IsZero() {
+		e.bytes(nil)
+		return
+	}
+	data, err := value.MarshalBinary()
+	if err != nil && e.err == nil {
+		e.err = BinaryError.AddCause(err)
+	}
+	e.bytes(data)
+}
+
+func (e *binaryEncoder) stackTrace(trace StackFrames) {
+	e.uvarint(uint64(len(trace)))
+	for _, frame := range trace {
+		e.stackFrame(frame)
+	}
+}
+
+func (e *binaryEncoder) stackFrame(frame StackFrame) {
+	e.string(frame.Function)
+	e.string(frame.File)
+	e.varint(int64(frame.Line))
+}
+
+func (e *binaryEncoder) goroutines(goroutines []Goroutine) {
+	e.uvarint(uint64(len(goroutines)))
+	for _, goroutine := range goroutines {
+		e.varint(int64(goroutine.ID))
+		e.string(goroutine.State)
+		e.varint(int64(goroutine.Wait))
+		e.stackTrace(goroutine.StackTrace)
+		e.stackFrame(goroutine.CreatedBy)
+		e.varint(int64(goroutine.CreatorID))
+		e.error(goroutine.Exception)
+	}
+}
+
+// ========================================
+
+// binaryDecoder reads the binary format of [EncodeBinary]. The first failure
+// is kept and every later read returns a zero value.
+type binaryDecoder struct {
+	data    []byte
+	strings []string
+	depth   int
+	err     error
+}
+
+func newBinaryDecoder(data []byte) (*binaryDecoder, error) {
+	if len(data) == 0 {
+		return nil, BinaryError.SetMessage("empty data")
+	}
+	if data[0] != binaryVersion {
+		return nil, BinaryError.SetMessage("unsupported version %d", data[0])
+	}
+	return &binaryDecoder{data: data[1:]}, nil
+}
+
+func (d *binaryDecoder) finish() error {
+	if d.err == nil && len(d.data) > 0 {
+		d.fail("%d bytes after the end", len(d.data))
+	}
+	return d.err
+}
+
+func (d *binaryDecoder) fail(message string, parameters ...any) {
+	if d.err == nil {
+		d.err = BinaryError.SetMessage(message, parameters...)
+	}
+	d.data = nil
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	value, length := binary.Uvarint(d.data)
+	if length <= 0 {
+		d.fail("truncated data")
+		return 0
+	}
+	d.data = d.data[length:]
+	return value
+}
+
+func (d *binaryDecoder) varint() int64 {
+	value, length := binary.Varint(d.data)
+	if length <= 0 {
+		d.fail("truncated data")
+		return 0
+	}
+	d.data = d.data[length:]
+	return value
+}
+
+// length reads the length of a list or a string, which cannot exceed the
+// remaining data since every element takes at least one byte.
+func (d *binaryDecoder) length() int {
+	length := d.uvarint()
+	if length > uint64(len(d.data)) {
+		d.fail("truncated data")
+		return 0
+	}
+	return int(length)
+}
+
+func (d *binaryDecoder) bytes() []byte {
+	length := d.length()
+	value := d.data[:length:length]
+	d.data = d.data[length:]
+	return value
+}
+
+func (d *binaryDecoder) string() string {
+	index := d.uvarint()
+	if index == 0 {
+		value := string(d.bytes())
+		if d.err == nil {
+			d.strings = append(d.strings, value)
+		}
+		return value
+	}
+	if index > uint64(len(d.strings)) {
+		d.fail("unknown string %d", index)
+		return ""
+	}
+	return d.strings[index-1]
+}
+
+func (d *binaryDecoder) error() Exception {
+	if d.depth++; d.depth > binaryMaxDepth {
+		d.fail("too deeply nested")
+		return nil
+	}
+	defer func() { d.depth-- }()
+	switch tag := d.uvarint(); tag {
+	case binaryNil:
+		return nil
+	case binaryString:
+		return String(d.string())
+	case binaryJoin:
+		return multipleErrors(d.errors())
+	case binaryFull:
+		return d.exception()
+	case binaryForeign:
+		return fullException{Message: d.string(), Stack: d.stackTrace()}
+	case binaryRegistered:
+		name, payload, source := d.string(), d.bytes(), d.exception()
+		if d.err != nil {
+			return nil
+		}
+		kind, ok := lookupBinaryName(name)
+		if !ok {
+			return source
+		}
+		decoded, err := kind.decode(source, payload)
+		if err != nil {
+			d.err = BinaryError.SetMessage("invalid payload of %s", name).AddCause(err)
+			d.data = nil
+			return nil
+		}
+		value := reflect.ValueOf(decoded)
+		if !value.IsValid() || value.Kind() == reflect.Pointer && value.IsNil() {
+			d.fail("invalid payload of %s", name)
+			return nil
+		}
+		if base := baseField(value); base != nil {
+			base.exception = source
+		}
+		return decoded
+	default:
+		d.fail("unknown tag %d", tag)
+		return nil
+	}
+}
+
+func (d *binaryDecoder) errors() []error {
+	length := d.length()
+	if length == 0 {
+		return nil
+	}
+	errors := make([]error, length)
+	for i := range errors {
+		errors[i] = d.error()
+	}
+	return errors
+}
+
+func (d *binaryDecoder) exception() fullException {
+	return fullException{
+		Type:       d.string(),
+		Message:    d.string(),
+		Causes:     d.errors(),
+		Suppressed: d.errors(),
+		Recovered:  d.recovered(),
+		Stack:      d.stackTrace(),
+		ID:         d.string(),
+		Time:       d.time(),
+		Goroutines: d.goroutines(),
+	}
+}
+
+func (d *binaryDecoder) recovered() any {
+	switch tag := d.uvarint(); tag {
+	case recoveredNil:
+		return nil
+	case recoveredError:
+		return d.error()
+	case recoveredString:
+		return d.string()
+	default:
+		d.fail("unknown recovered tag %d", tag)
+		return nil
+	}
+}
+
+func (d *binaryDecoder) time() time.Time {
+	var value time.Time
+	if data := d.bytes(); len(data) > 0 {
+		if err := value.UnmarshalBinary(data); err != nil && d.err == nil {
+			d.err = BinaryError.AddCause(err)
+		}
+	}
+	return value
+}
+
+func (d *binaryDecoder) stackTrace() StackFrames {
+	length := d.length()
+	if length == 0 {
+		return nil
+	}
+	trace := make(StackFrames, length)
+	for i := range trace {
+		trace[i] = d.stackFrame()
+	}
+	return trace
+}
+
+func (d *binaryDecoder) stackFrame() StackFrame {
+	return StackFrame{
+		Function: d.string(),
+		File:     d.string(),
+		Line:     int(d.varint()),
+	}
+}
+
+func (d *binaryDecoder) goroutines() []Goroutine {
+	length := d.length()
+	if length == 0 {
+		return nil
+	}
+	goroutines := make([]Goroutine, length)
+	for i := range goroutines {
+		goroutines[i] = Goroutine{
+			ID:         int(d.varint()),
+			State:      d.string(),
+			Wait:       time.Duration(d.varint()),
+			StackTrace: d.stackTrace(),
+			CreatedBy:  d.stackFrame(),
+			CreatorID:  int(d.varint()),
+			Exception:  d.error(),
+		}
+	}
+	return goroutines
+}
@@ -0,0 +1,181 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+func init() {
+	exception.RegisterBinary("OrderFailure",
+		func(err *OrderFailure) ([]byte, error) {
+			return []byte(err.OrderID), nil
+		},
+		func(source exception.Exception, payload []byte) (*OrderFailure, error) {
+			err := &OrderFailure{OrderID: string(payload)}
+			err.Init(err, source)
+			return err, nil
+		})
+	exception.RegisterBinary("NilFailure",
+		func(err *nilFailure) ([]byte, error) {
+			return nil, nil
+		},
+		func(source exception.Exception, payload []byte) (*nilFailure, error) {
+			return nil, nil
+		})
+}
+
+// nilFailure is a registered kind whose decode function returns nil.
+type nilFailure struct {
+	exception.Base
+}
+
+// withoutPC returns the frames without their program counters, which are not
+// encoded.
+func withoutPC(trace exception.StackFrames) exception.StackFrames {
+	trace = slices.Clone(trace)
+	for i := range trace {
+		trace[i].PC = 0
+	}
+	return trace
+}
+
+func roundTrip(t *testing.T, err error) exception.Exception {
+	t.Helper()
+	data, encodeErr := exception.EncodeBinary(err)
+	if encodeErr != nil {
+		t.Fatal(encodeErr)
+	}
+	decoded, decodeErr := exception.DecodeBinary(data)
+	if decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	return decoded
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	original := CloseError.
+		AddCause(ParseError, exception.Join(io.EOF, UsageError)).
+		AddSuppressed(pkgerrors.New("Test")).
+		SetRecovered(42).
+		FillStackTrace(0)
+	decoded := roundTrip(t, original)
+	if !errors.Is(decoded, CloseError) || decoded.Error() != original.Error() {
+		t.Errorf("Expected %v but got %v", original, decoded)
+	}
+	if decoded.GetID() != original.GetID() || !decoded.GetTime().Equal(original.GetTime()) {
+		t.Errorf("Expected the ID and the time to be kept but got %v at %v", decoded.GetID(), decoded.GetTime())
+	}
+	if trace := decoded.GetStackTrace(); !slices.Equal(trace, withoutPC(original.GetStackTrace())) {
+		t.Errorf("Expected the stack trace to be kept but got %v", trace)
+	}
+	if recovered := decoded.GetRecovered(); recovered != "42" {
+		t.Errorf("Expected the recovered value as a string but got %#v", recovered)
+	}
+	causes := decoded.GetCause()
+	// the causes of the Join are merged when added
+	if len(causes) != 3 || causes[0] != ParseError || causes[1].Error() != "EOF" || causes[2] != UsageError {
+		t.Errorf("Expected the causes to be kept but got %#v", causes)
+	}
+	suppressed := decoded.GetSuppressed()
+	if len(suppressed) != 1 || suppressed[0].Error() != "Test" || exception.StackTraceOf(suppressed[0]) == nil {
+		t.Errorf("Expected the foreign error with its stack trace but got %#v", suppressed)
+	}
+}
+
+func TestBinaryRegisteredKind(t *testing.T) {
+	original := NewOrderFailure("42").AddCause(io.EOF).FillStackTrace(0)
+	decoded := roundTrip(t, exception.Join(original))
+	var failure *OrderFailure
+	if !errors.As(decoded, &failure) || failure.OrderID != "42" {
+		t.Fatalf("Expected the registered kind to be kept but got %#v", decoded)
+	}
+	if failure.GetID() != original.GetID() || len(failure.GetCause()) != 1 || failure.GetStackTrace() == nil {
+		t.Errorf("Expected the common fields to be kept but got %#v", failure)
+	}
+	// the decoded exception is still copy-on-write
+	if modified, ok := failure.SetMessage("modified").(*OrderFailure); !ok || modified.OrderID != "42" || failure.GetMessage() == "modified" {
+		t.Errorf("Expected a modified copy but got %#v", modified)
+	}
+}
+
+func TestBinaryInterning(t *testing.T) {
+	err := CloseError.AddCause(ParseError.FillStackTrace(0), ParseError.FillStackTrace(0)).FillStackTrace(0)
+	data, encodeErr := exception.EncodeBinary(err)
+	if encodeErr != nil {
+		t.Fatal(encodeErr)
+	}
+	var text bytes.Buffer
+	if err := exception.WriteJSON(&text, err); err != nil {
+		t.Fatal(err)
+	}
+	if len(data)*2 > text.Len() {
+		t.Errorf("Expected the binary encoding to be much smaller than JSON but got %d against %d bytes", len(data), text.Len())
+	}
+}
+
+func TestBinaryGob(t *testing.T) {
+	type message struct {
+		Err   error
+		Trace exception.StackFrames
+	}
+	original := message{
+		Err:   exception.Join(CloseError.AddCause(ParseError).FillStackTrace(0), io.EOF),
+		Trace: exception.StackTrace(0),
+	}
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(original); err != nil {
+		t.Fatal(err)
+	}
+	var decoded message
+	if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(decoded.Err, ParseError) || decoded.Err.Error() != original.Err.Error() {
+		t.Errorf("Expected %v but got %v", original.Err, decoded.Err)
+	}
+	if !slices.Equal(decoded.Trace, withoutPC(original.Trace)) {
+		t.Errorf("Expected the stack trace to be kept but got %v", decoded.Trace)
+	}
+}
+
+func TestDecodeBinaryInvalid(t *testing.T) {
+	data, err := exception.EncodeBinary(CloseError.AddCause(ParseError))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, invalid := range [][]byte{nil, {0x7f}, data[:len(data)-1], append(slices.Clone(data), 0)} {
+		if _, err := exception.DecodeBinary(invalid); !errors.Is(err, exception.BinaryError) {
+			t.Errorf("Expected a BinaryError for %v but got %v", invalid, err)
+		}
+	}
+	if decoded, err := exception.DecodeBinary([]byte{1, 0}); decoded != nil || err != nil {
+		t.Errorf("Expected a nil exception but got %v, %v", decoded, err)
+	}
+}
+
+func TestDecodeBinaryNilKind(t *testing.T) {
+	original := new(nilFailure)
+	original.Init(original, CloseError)
+	data, err := exception.EncodeBinary(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded, err := exception.DecodeBinary(data); decoded != nil || !errors.Is(err, exception.BinaryError) ||
+		err.(exception.Exception).GetMessage() != "invalid payload of NilFailure" {
+		t.Errorf("Expected an invalid payload but got %#v, %v", decoded, err)
+	}
+}
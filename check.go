@@ -0,0 +1,114 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import "time"
+
+// Check panics if err is not nil, so that the error is propagated to the
+// nearest deferred [Handle] or [HandleRecover], which turns it back into a
+// returned error. The stack trace starting from the caller of [Check] is
+// attached to the returned error if it does not already have one.
+//
+// Typical usage:
+//
+//	func parse(input string) (result Config, err error) {
+//	    defer exception.Handle(&err)
+//	    header := exception.Must(parseHeader(input))
+//	    exception.Check(validate(header))
+//	    ...
+//	}
+func Check(err error) {
+	if err != nil {
+		check(err, 2)
+	}
+}
+
+// Must returns the value if err is nil, otherwise it panics like [Check]. The
+// stack trace starts from the caller of [Must].
+func Must[T any](value T, err error) T {
+	if err != nil {
+		check(err, 2)
+	}
+	return value
+}
+
+// checkedError marks the recovered value of a panic raised by [Check] or
+// [Must].
+type checkedError struct {
+	error
+}
+
+func check(err error, skip int) {
+	Panic(fullException{
+		Type:      string(PanicError),
+		Causes:    []error{err},
+		Recovered: checkedError{err},
+		Stack:     StackTrace(skip),
+		ID:        NewID(),
+		Time:      time.Now(),
+	})
+}
+
+// Handle recovers a panic raised by [Check] or [Must] and stores the checked
+// error in err. Any other panic is re-panicked untouched. It must be deferred
+// directly:
+//
+//	defer exception.Handle(&err)
+func Handle(err *error) {
+	if recovered := recover(); recovered != nil {
+		if checked, ok := handle(recovered); ok {
+			*err = checked
+		} else {
+			panic(recovered)
+		}
+	}
+}
+
+// HandleRecover behaves like [Handle], except that any other panic is also
+// recovered, converted with [Recover] and stored in err. It must be deferred
+// directly:
+//
+//	defer exception.HandleRecover(&err)
+//
+// The active [RecoveryPolicy] is honored like in [Recover].
+func HandleRecover(err *error) {
+	GetRecoveryPolicy().handleRecover(err, recover())
+}
+
+// handle returns the checked error, with the stack trace of the failing call
+// attached, if the recovered value was raised by [Check] or [Must].
+func handle(recovered any) (error, bool) {
+	marker, ok := recovered.(fullException)
+	if !ok {
+		return nil, false
+	}
+	checked, ok := marker.Recovered.(checkedError)
+	if !ok {
+		return nil, false
+	}
+	return attachStackTrace(checked.error, marker.Stack), true
+}
+
+// stackTraceAttacher is implemented by the exceptions of this package that can
+// take an already captured stack trace.
+type stackTraceAttacher interface {
+	withStackTrace(trace StackFrames) Exception
+}
+
+// attachStackTrace returns the error with the stack trace attached, or the error
+// itself if it already has a stack trace. Foreign errors are wrapped into an
+// [Exception] with the same message.
+func attachStackTrace(err error, trace StackFrames) error {
+	exception := wrap(err)
+	if exception.GetStackTrace() != nil {
+		return exception
+	}
+	if attacher, ok := exception.(stackTraceAttacher); ok {
+		return attacher.withStackTrace(trace)
+	}
+	return exception
+}
@@ -0,0 +1,67 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+const ParseError = exception.String("ParseError: invalid number")
+
+func parseChecked(input string) (result int, err error) {
+	defer exception.Handle(&err)
+	result = exception.Must(strconv.Atoi(input))
+	if result < 0 {
+		exception.Check(ParseError)
+	}
+	return result, nil
+}
+
+func TestMust(t *testing.T) {
+	if result, err := parseChecked("42"); err != nil || result != 42 {
+		t.Errorf("Expected 42 without error but got %d and %v", result, err)
+	}
+	_, err := parseChecked("x")
+	var numError *strconv.NumError
+	if !errors.As(err, &numError) {
+		t.Fatalf("Expected to get the strconv error but got %#v", err)
+	}
+	checkStackTrace(t, err.(exception.Exception).GetStackTrace(), "/go-exception_test.parseChecked")
+}
+
+func TestCheck(t *testing.T) {
+	_, err := parseChecked("-1")
+	if !errors.Is(err, ParseError) {
+		t.Fatalf("Expected to get ParseError but got %#v", err)
+	}
+	checkStackTrace(t, err.(exception.Exception).GetStackTrace(), "/go-exception_test.parseChecked")
+}
+
+func TestHandleRepanics(t *testing.T) {
+	defer func() {
+		if recovered := recover(); recovered != "Test" {
+			t.Errorf("Expected genuine panic to be re-panicked but got %#v", recovered)
+		}
+	}()
+	var err error
+	defer exception.Handle(&err)
+	panic("Test")
+}
+
+func TestHandleRecover(t *testing.T) {
+	err := func() (err error) {
+		defer exception.HandleRecover(&err)
+		panic("Test")
+	}()
+	if !errors.Is(err, exception.PanicError) {
+		t.Errorf("Expected genuine panic to be recovered but got %#v", err)
+	}
+}
@@ -0,0 +1,327 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/thanhminhmr/go-exception"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// ExtractError is the type of the errors found while loading the packages.
+const ExtractError = exception.String("ExtractError")
+
+// exceptionPackage is the import path of the exception package.
+const exceptionPackage = "github.com/thanhminhmr/go-exception"
+
+// Kinds of catalog entries.
+const (
+	KindString   = "string"   // an exception.String constant
+	KindTemplate = "template" // an exception.Template constant
+	KindBase     = "base"     // a struct embedding exception.Base
+	KindTyped    = "typed"    // a payload of exception.NewTyped
+)
+
+// Entry is a declaration producing exceptions.
+type Entry struct {
+	// Name is the Go name of the declaration.
+	Name string `json:"name"`
+
+	// Kind is the kind of the declaration, one of the Kind constants.
+	Kind string `json:"kind"`
+
+	// Type is the type of the exceptions, empty if it is not known statically.
+	Type string `json:"type"`
+
+	// Message is the message, or the message pattern of a template.
+	Message string `json:"message,omitempty"`
+
+	// Package is the import path of the declaring package.
+	Package string `json:"package"`
+
+	// Position is the position of the declaration, empty for declarations of
+	// packages that were not scanned.
+	Position string `json:"position,omitempty"`
+
+	// Doc is the doc comment of the declaration.
+	Doc string `json:"doc,omitempty"`
+
+	// CallSites lists the positions, in the scanned packages, where the
+	// constant is referenced, the struct is initialized or the payload is passed
+	// to exception.NewTyped.
+	CallSites []string `json:"call_sites,omitempty"`
+
+	sites []token.Position
+}
+
+// Extract loads the packages matching the patterns from the directory and
+// returns the declarations producing exceptions that they declare or use,
+// sorted by package and name. Positions are relative to the directory.
+func Extract(directory string, patterns ...string) ([]*Entry, error) {
+	config := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir: directory,
+	}
+	loaded, err := packages.Load(config, patterns...)
+	if err != nil {
+		return nil, ExtractError.AddCause(err)
+	}
+	var errs []error
+	packages.Visit(loaded, nil, func(p *packages.Package) {
+		for _, err := range p.Errors {
+			errs = append(errs, err)
+		}
+	})
+	if len(errs) > 0 {
+		return nil, ExtractError.AddCause(errs...)
+	}
+	directory, err = filepath.Abs(directory)
+	if err != nil {
+		return nil, ExtractError.AddCause(err)
+	}
+	x := &extractor{directory: directory, scanned: map[*types.Package]bool{}, docs: map[types.Object]string{}, entries: map[types.Object]*Entry{}}
+	for _, p := range loaded {
+		x.scanned[p.Types] = true
+	}
+	for _, p := range loaded {
+		x.declarations(p)
+	}
+	for _, p := range loaded {
+		x.uses(p)
+	}
+	entries := make([]*Entry, 0, len(x.entries))
+	for _, entry := range x.entries {
+		slices.SortFunc(entry.sites, func(a, b token.Position) int {
+			return cmp.Or(cmp.Compare(a.Filename, b.Filename), cmp.Compare(a.Offset, b.Offset))
+		})
+		for _, site := range entry.sites {
+			entry.CallSites = append(entry.CallSites, x.position(site))
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b *Entry) int {
+		return cmp.Or(cmp.Compare(a.Package, b.Package), cmp.Compare(a.Name, b.Name))
+	})
+	return entries, nil
+}
+
+type extractor struct {
+	directory string
+	fileSet   *token.FileSet
+	scanned   map[*types.Package]bool
+	docs      map[types.Object]string
+	entries   map[types.Object]*Entry
+}
+
+// declarations records the doc comments of the declarations of the package,
+// and the constants and the structs producing exceptions.
+func (x *extractor) declarations(p *packages.Package) {
+	x.fileSet = p.Fset
+	for _, file := range p.Syntax {
+		for _, declaration := range file.Decls {
+			group, ok := declaration.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range group.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						object := p.TypesInfo.Defs[name]
+						x.docs[object] = doc(group, spec.Doc)
+						x.entry(object)
+					}
+				case *ast.TypeSpec:
+					object := p.TypesInfo.Defs[spec.Name]
+					x.docs[object] = doc(group, spec.Doc)
+					if object != nil && embedsBase(object.Type()) {
+						x.add(object, KindBase)
+					}
+				}
+			}
+		}
+	}
+}
+
+// uses records the call sites found in the package: the uses of the constants,
+// the calls to Init of the structs embedding exception.Base, which also give
+// their type, and the calls to exception.NewTyped.
+func (x *extractor) uses(p *packages.Package) {
+	x.fileSet = p.Fset
+	for identifier, object := range p.TypesInfo.Uses {
+		if entry := x.entry(object); entry != nil {
+			entry.sites = append(entry.sites, x.fileSet.Position(identifier.Pos()))
+		}
+	}
+	for _, file := range p.Syntax {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			switch function := typeutil.Callee(p.TypesInfo, call); {
+			case isFunction(function, "Init") && len(call.Args) == 2:
+				x.init(p.TypesInfo, call)
+			case isFunction(function, "NewTyped"):
+				x.newTyped(p.TypesInfo, call)
+			}
+			return true
+		})
+	}
+}
+
+func (x *extractor) init(info *types.Info, call *ast.CallExpr) {
+	pointer, ok := info.TypeOf(call.Args[0]).(*types.Pointer)
+	if !ok {
+		return
+	}
+	named, ok := pointer.Elem().(*types.Named)
+	if !ok || !embedsBase(named) {
+		return
+	}
+	entry := x.add(named.Obj(), KindBase)
+	entry.sites = append(entry.sites, x.fileSet.Position(call.Pos()))
+	if value := info.Types[call.Args[1]].Value; value != nil && value.Kind() == constant.String {
+		source := exception.String(constant.StringVal(value))
+		entry.Type, entry.Message = source.GetType(), source.GetMessage()
+	}
+}
+
+func (x *extractor) newTyped(info *types.Info, call *ast.CallExpr) {
+	identifier := calleeIdentifier(call.Fun)
+	if identifier == nil {
+		return
+	}
+	instance, ok := info.Instances[identifier]
+	if !ok || instance.TypeArgs.Len() != 1 {
+		return
+	}
+	// only named payloads have a declaration to document
+	named, ok := instance.TypeArgs.At(0).(*types.Named)
+	if !ok {
+		return
+	}
+	entry := x.add(named.Obj(), KindTyped)
+	entry.Type = named.Obj().Name()
+	entry.sites = append(entry.sites, x.fileSet.Position(call.Pos()))
+}
+
+// entry returns the entry of a constant of type exception.String or
+// exception.Template, adding it if needed, or nil if the object is not such a
+// constant.
+func (x *extractor) entry(object types.Object) *Entry {
+	value, ok := object.(*types.Const)
+	if !ok || value.Val().Kind() != constant.String {
+		return nil
+	}
+	if entry, ok := x.entries[object]; ok {
+		return entry
+	}
+	var entry *Entry
+	switch {
+	case isNamed(value.Type(), "String"):
+		entry = x.add(object, KindString)
+	case isNamed(value.Type(), "Template"):
+		entry = x.add(object, KindTemplate)
+	default:
+		return nil
+	}
+	source := exception.String(constant.StringVal(value.Val()))
+	entry.Type, entry.Message = source.GetType(), source.GetMessage()
+	return entry
+}
+
+func (x *extractor) add(object types.Object, kind string) *Entry {
+	if entry, ok := x.entries[object]; ok {
+		return entry
+	}
+	entry := &Entry{Name: object.Name(), Kind: kind, Doc: x.docs[object]}
+	if object.Pkg() != nil {
+		entry.Package = object.Pkg().Path()
+	}
+	if x.scanned[object.Pkg()] {
+		entry.Position = x.position(x.fileSet.Position(object.Pos()))
+	}
+	x.entries[object] = entry
+	return entry
+}
+
+// position returns the position as file:line:column, with the file relative
+// to the directory when possible.
+func (x *extractor) position(position token.Position) string {
+	file := position.Filename
+	if relative, err := filepath.Rel(x.directory, file); err == nil && !strings.HasPrefix(relative, "..") {
+		file = filepath.ToSlash(relative)
+	}
+	return fmt.Sprintf("%s:%d:%d", file, position.Line, position.Column)
+}
+
+// ========================================
+
+// doc returns the doc comment of a specification, or the one of its group when
+// the group has a single specification, following go/doc.
+func doc(group *ast.GenDecl, comment *ast.CommentGroup) string {
+	if comment == nil && len(group.Specs) == 1 {
+		comment = group.Doc
+	}
+	return strings.TrimSpace(comment.Text())
+}
+
+// isNamed reports whether the type is the named type of the exception package.
+func isNamed(t types.Type, name string) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == name && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == exceptionPackage
+}
+
+// embedsBase reports whether the type is a struct embedding exception.Base
+// directly.
+func embedsBase(t types.Type) bool {
+	structure, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for field := range structure.Fields() {
+		if field.Embedded() && isNamed(field.Type(), "Base") {
+			return true
+		}
+	}
+	return false
+}
+
+// isFunction reports whether the object is the function or method of the
+// exception package with the given name.
+func isFunction(object types.Object, name string) bool {
+	function, ok := object.(*types.Func)
+	return ok && function.Name() == name && function.Pkg() != nil && function.Pkg().Path() == exceptionPackage
+}
+
+// calleeIdentifier returns the identifier naming the called function, with its
+// package qualifier and type arguments removed.
+func calleeIdentifier(function ast.Expr) *ast.Ident {
+	switch function := ast.Unparen(function).(type) {
+	case *ast.Ident:
+		return function
+	case *ast.SelectorExpr:
+		return function.Sel
+	case *ast.IndexExpr:
+		return calleeIdentifier(function.X)
+	case *ast.IndexListExpr:
+		return calleeIdentifier(function.X)
+	}
+	return nil
+}
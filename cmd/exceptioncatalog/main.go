@@ -0,0 +1,79 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+// Command exceptioncatalog lists the exception types that a set of packages
+// can produce, as JSON or as a Markdown reference:
+//
+//	exceptioncatalog -format markdown ./... > ERRORS.md
+//
+// The catalog covers the [exception.String] and [exception.Template] constants
+// declared or used by the packages, the structs embedding [exception.Base],
+// whose type is read from their Init calls, and the payloads passed to
+// [exception.NewTyped]. Each entry lists its type, its message, its declaring
+// package, its doc comment and its call sites.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+// UsageError is the type of the errors caused by invalid command line
+// arguments.
+const UsageError = exception.String("UsageError")
+
+// OutputError is the type of the errors found while writing the catalog.
+const OutputError = exception.String("OutputError")
+
+func main() {
+	program := exception.Program{ExitCodes: map[string]int{
+		UsageError.GetType():   exception.ExitUsage,
+		ExtractError.GetType(): exception.ExitDataError,
+	}}
+	os.Exit(program.Run(run))
+}
+
+func run(context.Context) (err error) {
+	format := flag.String("format", "json", "output format, json or markdown")
+	output := flag.String("output", "", "output file (default: the standard output)")
+	flag.Usage = func() {
+		_, _ = flag.CommandLine.Output().Write([]byte("usage: exceptioncatalog [flags] [packages]\n"))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *format != "json" && *format != "markdown" {
+		flag.Usage()
+		return UsageError + ": unknown format " + exception.String(*format)
+	}
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	entries, err := Extract(".", patterns...)
+	if err != nil {
+		return err
+	}
+	var writer io.Writer = os.Stdout
+	if *output != "" {
+		file, createErr := os.Create(*output)
+		if createErr != nil {
+			return OutputError.AddCause(createErr)
+		}
+		defer exception.Close(&err, file)
+		writer = file
+	}
+	if *format == "markdown" {
+		return WriteMarkdown(writer, entries)
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
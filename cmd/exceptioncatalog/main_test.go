@@ -0,0 +1,57 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestExtract(t *testing.T) {
+	entries, err := Extract("testdata", "./orders", "./billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden(t, filepath.Join("testdata", "catalog.json.golden"), append(actual, '\n'))
+	var markdown bytes.Buffer
+	if err := WriteMarkdown(&markdown, entries); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, filepath.Join("testdata", "catalog.md.golden"), markdown.Bytes())
+}
+
+func golden(t *testing.T, path string, actual []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("Output differs from %s, run the tests with -update to see the difference:\n%s", path, actual)
+	}
+}
+
+func TestExtractInvalid(t *testing.T) {
+	if _, err := Extract("testdata", "./missing"); err == nil {
+		t.Errorf("Expected an error for a missing package")
+	}
+}
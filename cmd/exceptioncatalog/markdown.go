@@ -0,0 +1,51 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteMarkdown writes the entries as a Markdown reference, with one section
+// per package.
+func WriteMarkdown(writer io.Writer, entries []*Entry) error {
+	buffer := bufio.NewWriter(writer)
+	printf := func(format string, parameters ...any) {
+		_, _ = fmt.Fprintf(buffer, format, parameters...)
+	}
+	printf("# Exception catalog\n")
+	for i, entry := range entries {
+		if i == 0 || entries[i-1].Package != entry.Package {
+			printf("\n## %s\n", entry.Package)
+		}
+		printf("\n### %s\n\n", entry.Name)
+		if entry.Doc != "" {
+			printf("%s\n\n", entry.Doc)
+		}
+		if entry.Type != "" {
+			printf("- Type: `%s`\n", entry.Type)
+		} else {
+			printf("- Type: unknown\n")
+		}
+		if entry.Message != "" {
+			printf("- Message: `%s`\n", entry.Message)
+		}
+		printf("- Kind: %s\n", entry.Kind)
+		if entry.Position != "" {
+			printf("- Declared at: %s\n", entry.Position)
+		}
+		if len(entry.CallSites) > 0 {
+			printf("- Call sites:\n")
+			for _, site := range entry.CallSites {
+				printf("  - %s\n", site)
+			}
+		}
+	}
+	return buffer.Flush()
+}
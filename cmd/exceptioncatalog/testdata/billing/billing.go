@@ -0,0 +1,16 @@
+package billing
+
+import (
+	"errors"
+
+	"github.com/thanhminhmr/go-exception"
+
+	"github.com/thanhminhmr/go-exception/cmd/exceptioncatalog/testdata/orders"
+)
+
+func Charge(err error) error {
+	if errors.Is(err, orders.OrderError) {
+		return exception.PanicError
+	}
+	return exception.NewTyped[orders.QuotaExceeded](orders.QuotaExceeded{})
+}
@@ -0,0 +1,42 @@
+// Package orders declares exceptions for the catalog tests.
+package orders
+
+import "github.com/thanhminhmr/go-exception"
+
+// OrderError is returned when an order cannot be processed.
+const OrderError = exception.String("OrderError: order cannot be processed")
+
+const (
+	// NotFoundError is returned for unknown orders.
+	NotFoundError = exception.Template("NotFound: order %s not found")
+
+	internal = exception.String("Internal")
+)
+
+// OrderFailure is an order exception carrying the order ID.
+type OrderFailure struct {
+	exception.Base
+	OrderID string
+}
+
+func NewOrderFailure(orderID string) *OrderFailure {
+	err := &OrderFailure{OrderID: orderID}
+	err.Init(err, OrderError)
+	return err
+}
+
+// QuotaExceeded is the payload of quota exceptions.
+type QuotaExceeded struct {
+	Limit int
+}
+
+func Quota(limit int) error {
+	return exception.NewTyped(QuotaExceeded{Limit: limit})
+}
+
+func Find(id string) error {
+	if id == "" {
+		return internal
+	}
+	return NotFoundError.Format(id)
+}
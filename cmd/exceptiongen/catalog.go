@@ -0,0 +1,128 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thanhminhmr/go-exception"
+	"github.com/thanhminhmr/go-exception/internal/verbs"
+	"gopkg.in/yaml.v3"
+)
+
+// CatalogError is the type of the errors found while reading a catalog.
+const CatalogError = exception.String("CatalogError")
+
+// Catalog is the declaration file read by the generator.
+type Catalog struct {
+	Exceptions []Entry `json:"exceptions" yaml:"exceptions"`
+}
+
+// Entry declares one exception.
+type Entry struct {
+	// Type is the type of the exception.
+	Type string `json:"type" yaml:"type"`
+
+	// Name is the Go name of the exception, used for its constant (with an
+	// "Error" suffix), its payload and its constructor. When empty, the type is
+	// used, without its "Error" suffix if any.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Message is the message of the exception, a format string consuming the
+	// parameters in order.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	HTTP      int    `json:"http,omitempty" yaml:"http,omitempty"`
+	GRPC      string `json:"grpc,omitempty" yaml:"grpc,omitempty"`
+	Severity  string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Retryable bool   `json:"retryable,omitempty" yaml:"retryable,omitempty"`
+	Doc       string `json:"doc,omitempty" yaml:"doc,omitempty"`
+
+	Parameters []Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// Parameter declares a parameter of an exception message.
+type Parameter struct {
+	// Name is the Go name of the parameter. The payload field uses it with an
+	// upper case first letter.
+	Name string `json:"name" yaml:"name"`
+
+	// Type is the Go type of the parameter, such as "string" or "time.Duration".
+	Type string `json:"type" yaml:"type"`
+
+	Doc string `json:"doc,omitempty" yaml:"doc,omitempty"`
+}
+
+// ReadCatalog reads and validates a catalog. Files with a ".json" extension
+// are read as JSON, every other file as YAML.
+func ReadCatalog(path string) (catalog *Catalog, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, CatalogError.AddCause(err)
+	}
+	defer exception.Close(&err, file)
+	catalog = new(Catalog)
+	if filepath.Ext(path) == ".json" {
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(catalog)
+	} else {
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)
+		err = decoder.Decode(catalog)
+	}
+	if err != nil {
+		return nil, exception.Errorf(CatalogError, "%s", path).AddCause(err)
+	}
+	if err := catalog.validate(); err != nil {
+		return nil, exception.Errorf(CatalogError, "%s", path).AddCause(err)
+	}
+	return catalog, nil
+}
+
+func (c *Catalog) validate() error {
+	var errs []error
+	types := map[string]bool{}
+	names := map[string]bool{}
+	for i := range c.Exceptions {
+		entry := &c.Exceptions[i]
+		entry.Doc = strings.TrimSpace(entry.Doc)
+		if entry.Name == "" {
+			entry.Name = strings.TrimSuffix(entry.Type, "Error")
+		}
+		switch {
+		case entry.Type == "" || strings.Contains(entry.Type, ":") || strings.Contains(entry.Type, "%"):
+			errs = append(errs, exception.Errorf(CatalogError, "exception %d: invalid type %q", i, entry.Type))
+		case !token.IsIdentifier(entry.Name) || !token.IsExported(entry.Name):
+			errs = append(errs, exception.Errorf(CatalogError, "%s: invalid name %q, an exported Go identifier is required", entry.Type, entry.Name))
+		case types[entry.Type]:
+			errs = append(errs, exception.Errorf(CatalogError, "%s: type declared twice", entry.Type))
+		case names[entry.Name]:
+			errs = append(errs, exception.Errorf(CatalogError, "%s: name %s declared twice", entry.Type, entry.Name))
+		}
+		types[entry.Type], names[entry.Name] = true, true
+		for j := range entry.Parameters {
+			parameter := &entry.Parameters[j]
+			parameter.Doc = strings.TrimSpace(parameter.Doc)
+			if !token.IsIdentifier(parameter.Name) {
+				errs = append(errs, exception.Errorf(CatalogError, "%s: invalid parameter name %q", entry.Type, parameter.Name))
+			}
+			if _, err := parser.ParseExpr(parameter.Type); err != nil {
+				errs = append(errs, exception.Errorf(CatalogError, "%s: invalid type %q of parameter %s", entry.Type, parameter.Type, parameter.Name))
+			}
+		}
+		if count, ok := verbs.Count(entry.Message); ok && count != len(entry.Parameters) {
+			errs = append(errs, exception.Errorf(CatalogError, "%s: the message consumes %d arguments but %d parameters are declared", entry.Type, count, len(entry.Parameters)))
+		}
+	}
+	return exception.Join(errs...)
+}
@@ -0,0 +1,143 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/imports"
+)
+
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{
+	"comment":  comment,
+	"constant": constant,
+	"field":    field,
+	"quote":    strconv.Quote,
+	"unescape": unescape,
+}).Parse(`// Code generated by exceptiongen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/thanhminhmr/go-exception"
+
+const (
+{{- range $i, $e := .Exceptions}}
+	{{- if $i}}
+{{end}}
+	// {{.Name}}Error is the {{.Type}} exception.
+	{{- with .Doc}}
+	//
+	{{comment .}}
+	{{- end}}
+	{{- if .Parameters}}
+	{{.Name}}Error = exception.Template({{quote (constant .Type .Message)}})
+	{{- else}}
+	{{.Name}}Error = exception.String({{quote (constant .Type (unescape .Message))}})
+	{{- end}}
+{{- end}}
+)
+{{range .Exceptions}}{{if .Parameters}}
+// {{.Name}} is the payload of the [{{.Name}}Error] exceptions.
+type {{.Name}} struct {
+{{- range .Parameters}}
+	{{- with .Doc}}
+	{{comment .}}
+	{{- end}}
+	{{field .Name}} {{.Type}}
+{{- end}}
+}
+
+// New{{.Name}} creates the [{{.Name}}Error] exception carrying its parameters.
+func New{{.Name}}({{range $i, $p := .Parameters}}{{if $i}}, {{end}}{{$p.Name}} {{$p.Type}}{{end}}) *exception.Typed[{{.Name}}] {
+	return exception.TypedFrom({{.Name}}Error.Format({{range $i, $p := .Parameters}}{{if $i}}, {{end}}{{$p.Name}}{{end}}), {{.Name}}{
+	{{- range $i, $p := .Parameters}}{{if $i}}, {{end}}{{field $p.Name}}: {{$p.Name}}{{end -}}
+	})
+}
+{{end}}{{end}}
+func init() {
+	exception.Register(
+{{- range .Exceptions}}
+		exception.Definition{
+			Type: {{quote .Type}},
+			{{- with .Message}}
+			Message: {{quote .}},
+			{{- end}}
+			{{- with .HTTP}}
+			HTTPStatus: {{.}},
+			{{- end}}
+			{{- with .GRPC}}
+			GRPCCode: {{quote .}},
+			{{- end}}
+			{{- with .Severity}}
+			Severity: {{quote .}},
+			{{- end}}
+			{{- if .Retryable}}
+			Retryable: true,
+			{{- end}}
+			{{- with .Doc}}
+			Doc: {{quote .}},
+			{{- end}}
+		},
+{{- end}}
+	)
+}
+`))
+
+// GenerateGo returns the formatted Go source declaring the exceptions of the
+// catalog.
+func GenerateGo(catalog *Catalog, packageName string, source string) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := goTemplate.Execute(&buffer, struct {
+		*Catalog
+		Package string
+		Source  string
+	}{catalog, packageName, source})
+	if err != nil {
+		return nil, CatalogError.AddCause(err)
+	}
+	// imports the packages of the parameter types
+	formatted, err := imports.Process("", buffer.Bytes(), nil)
+	if err != nil {
+		return nil, CatalogError.SetMessage("invalid generated code").AddCause(err)
+	}
+	return formatted, nil
+}
+
+// constant returns the value of the constant declaring an exception.
+func constant(typ string, message string) string {
+	if message == "" {
+		return typ
+	}
+	return typ + ": " + message
+}
+
+// unescape returns the message of an exception without parameters as it is
+// formatted, since a String is not a format string.
+func unescape(message string) string {
+	return strings.ReplaceAll(message, "%%", "%")
+}
+
+// comment returns the text as Go line comments.
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// field returns the exported name of the payload field of a parameter.
+func field(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return fmt.Sprintf("%c%s", unicode.ToUpper(first), name[size:])
+}
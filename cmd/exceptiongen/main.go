@@ -0,0 +1,92 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+// Command exceptiongen generates exception declarations from a catalog, so that
+// the code and the error reference of an API share a single source of truth. It
+// is meant to be run by go generate:
+//
+//	//go:generate go run github.com/thanhminhmr/go-exception/cmd/exceptiongen -markdown ERRORS.md errors.yaml
+//
+// The catalog is a YAML or JSON file listing the exceptions of the package:
+//
+//	exceptions:
+//	  - type: OrderNotFound
+//	    message: order %s not found
+//	    http: 404
+//	    grpc: NotFound
+//	    severity: error
+//	    doc: The order does not exist or belongs to another customer.
+//	    parameters:
+//	      - name: orderID
+//	        type: string
+//
+// For each exception, a [exception.String] constant, or a [exception.Template]
+// constant when it has parameters, is generated. Exceptions with parameters
+// also get a payload struct and a constructor returning an [exception.Typed]
+// exception carrying it. Every exception is registered with
+// [exception.Register], and the optional Markdown reference documents them.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+// UsageError is the type of the errors caused by invalid command line
+// arguments.
+const UsageError = exception.String("UsageError")
+
+func main() {
+	program := exception.Program{ExitCodes: map[string]int{
+		UsageError.GetType():   exception.ExitUsage,
+		CatalogError.GetType(): exception.ExitDataError,
+	}}
+	os.Exit(program.Run(run))
+}
+
+func run(context.Context) error {
+	output := flag.String("output", "", "generated Go file (default: the catalog name with a _gen.go suffix)")
+	markdown := flag.String("markdown", "", "generated Markdown reference (default: none)")
+	packageName := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated Go file")
+	flag.Usage = func() {
+		_, _ = flag.CommandLine.Output().Write([]byte("usage: exceptiongen [flags] catalog\n"))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return UsageError + ": expected exactly one catalog"
+	}
+	if *packageName == "" {
+		return UsageError + ": the package is unknown outside of go generate, use -package"
+	}
+	path := flag.Arg(0)
+	if *output == "" {
+		*output = strings.TrimSuffix(path, filepath.Ext(path)) + "_gen.go"
+	}
+	catalog, err := ReadCatalog(path)
+	if err != nil {
+		return err
+	}
+	source, err := GenerateGo(catalog, *packageName, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*output, source, 0o644); err != nil {
+		return CatalogError.AddCause(err)
+	}
+	if *markdown != "" {
+		if err := os.WriteFile(*markdown, GenerateMarkdown(catalog, filepath.Base(path)), 0o644); err != nil {
+			return CatalogError.AddCause(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate(t *testing.T) {
+	catalog, err := ReadCatalog(filepath.Join("testdata", "orders.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := GenerateGo(catalog, "orders", "orders.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden(t, filepath.Join("testdata", "orders_gen.go.golden"), source)
+	golden(t, filepath.Join("testdata", "orders.md.golden"), GenerateMarkdown(catalog, "orders.yaml"))
+	typeCheck(t, "orders_gen.go", source)
+}
+
+// typeCheck fails the test if the generated source does not compile against
+// the exception package.
+func typeCheck(t *testing.T, name string, source []byte) {
+	t.Helper()
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, name, source, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fileSet, "source", nil)}
+	if _, err := config.Check(file.Name.Name, fileSet, []*ast.File{file}, nil); err != nil {
+		t.Errorf("Expected the generated source to compile but got %v", err)
+	}
+}
+
+func golden(t *testing.T, path string, actual []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("Output differs from %s, run the tests with -update to see the difference:\n%s", path, actual)
+	}
+}
+
+func TestReadCatalogInvalid(t *testing.T) {
+	_, err := ReadCatalog(filepath.Join("testdata", "invalid.json"))
+	if !errors.Is(err, CatalogError) {
+		t.Fatalf("Expected a catalog error but got %#v", err)
+	}
+	for _, expected := range []string{
+		`invalid type "IOError:read"`,
+		"the message consumes 2 arguments but 1 parameters are declared",
+		"name Duplicate declared twice",
+		`invalid parameter name "type"`,
+		`invalid type "[]" of parameter type`,
+	} {
+		if !strings.Contains(errorTree(err), expected) {
+			t.Errorf("Expected %q in %s", expected, errorTree(err))
+		}
+	}
+}
+
+// errorTree returns the messages of the error and all of its causes.
+func errorTree(err error) string {
+	messages := []string{err.Error()}
+	if unwrapped, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, cause := range unwrapped.Unwrap() {
+			messages = append(messages, errorTree(cause))
+		}
+	}
+	return strings.Join(messages, "\n")
+}
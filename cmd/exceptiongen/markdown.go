@@ -0,0 +1,95 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GenerateMarkdown returns the Markdown error reference of the catalog: a
+// summary table followed by one section per exception.
+func GenerateMarkdown(catalog *Catalog, source string) []byte {
+	var builder strings.Builder
+	printf := func(format string, parameters ...any) {
+		_, _ = fmt.Fprintf(&builder, format, parameters...)
+	}
+	printf("<!-- Code generated by exceptiongen from %s. DO NOT EDIT. -->\n\n", source)
+	printf("# Error reference\n\n")
+	printf("| Type | HTTP | gRPC | Severity | Retryable |\n")
+	printf("| ---- | ---- | ---- | -------- | --------- |\n")
+	for _, entry := range catalog.Exceptions {
+		printf("| [%s](#%s) | %s | %s | %s | %s |\n", entry.Type, anchor(entry.Type),
+			orDash(entry.HTTP), orDash(entry.GRPC), orDash(entry.Severity), yesNo(entry.Retryable))
+	}
+	for _, entry := range catalog.Exceptions {
+		printf("\n## %s\n\n", entry.Type)
+		if entry.Doc != "" {
+			printf("%s\n\n", strings.TrimSpace(entry.Doc))
+		}
+		if message := entry.Message; message != "" {
+			if len(entry.Parameters) == 0 {
+				message = unescape(message)
+			}
+			printf("- Message: `%s`\n", message)
+		}
+		printf("- Go: `%sError`", entry.Name)
+		if len(entry.Parameters) > 0 {
+			printf(", `New%s`", entry.Name)
+		}
+		printf("\n")
+		if entry.HTTP != 0 {
+			printf("- HTTP status: %d\n", entry.HTTP)
+		}
+		if entry.GRPC != "" {
+			printf("- gRPC code: %s\n", entry.GRPC)
+		}
+		if entry.Severity != "" {
+			printf("- Severity: %s\n", entry.Severity)
+		}
+		printf("- Retryable: %s\n", yesNo(entry.Retryable))
+		if len(entry.Parameters) > 0 {
+			printf("\n| Parameter | Type | Description |\n")
+			printf("| --------- | ---- | ----------- |\n")
+			for _, parameter := range entry.Parameters {
+				printf("| %s | `%s` | %s |\n", parameter.Name, parameter.Type, strings.ReplaceAll(strings.TrimSpace(parameter.Doc), "\n", " "))
+			}
+		}
+	}
+	return []byte(builder.String())
+}
+
+// anchor returns the anchor of a Markdown heading, as generated by GitHub.
+func anchor(heading string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '-'
+		case r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return -1
+		}
+	}, heading)
+}
+
+func orDash[T comparable](value T) string {
+	var zero T
+	if value == zero {
+		return "-"
+	}
+	return fmt.Sprint(value)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,133 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"cmp"
+	"go/constant"
+	"go/types"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// separator between type and message, as used by exception.String
+const separator = ": "
+
+// ConstantAnalyzer reports malformed exception.String and exception.Template
+// constants: missing or doubled separators, types not following the naming
+// convention, types depending on template parameters, and types also declared
+// by another constant of the package or by a dependency, which errors.Is cannot
+// tell apart.
+//
+// The analyzer runs on one package at a time, so duplicate types are only found
+// within a package and against the packages it imports: two packages that do
+// not import each other may declare the same type unnoticed.
+var ConstantAnalyzer = &analysis.Analyzer{
+	Name: "exceptionconst",
+	Doc: "report malformed exception.String and exception.Template constants\n\n" +
+		"The type of an exception is the part of the constant before the first \": \" separator, " +
+		"so a typo such as \"IOError:read failed\" silently becomes a type-only exception.\n\n" +
+		"Duplicate types are only reported within a package and against the packages it imports, " +
+		"directly or not. Sibling packages that do not import each other are never compared.",
+	Run:       runConstant,
+	FactTypes: []analysis.Fact{new(declaredTypes)},
+}
+
+// typePattern is the naming convention of exception types.
+var typePattern string
+
+func init() {
+	ConstantAnalyzer.Flags.StringVar(&typePattern, "typepattern", `^[A-Za-z][A-Za-z0-9 ._-]*$`,
+		"regular expression that exception types must match")
+}
+
+// declaredTypes is the fact of a package listing the exception types declared by
+// its constants, each one with the name of the first constant declaring it.
+type declaredTypes struct {
+	Types map[string]string
+}
+
+func (*declaredTypes) AFact() {}
+
+func (f *declaredTypes) String() string {
+	types := make([]string, 0, len(f.Types))
+	for t := range f.Types {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return "declaredTypes(" + strings.Join(types, ", ") + ")"
+}
+
+func runConstant(pass *analysis.Pass) (any, error) {
+	pattern, err := regexp.Compile(typePattern)
+	if err != nil {
+		return nil, err
+	}
+	// exception types declared by the dependencies
+	imported := map[string]string{}
+	for _, fact := range pass.AllPackageFacts() {
+		if declared, ok := fact.Fact.(*declaredTypes); ok && fact.Package != pass.Pkg {
+			for t, name := range declared.Types {
+				imported[t] = name
+			}
+		}
+	}
+	declared := &declaredTypes{Types: map[string]string{}}
+	for _, object := range exceptionConstants(pass.Pkg.Scope()) {
+		isTemplate := isNamed(object.Type(), "Template")
+		value := constant.StringVal(object.Val())
+		t, message, _ := strings.Cut(value, separator)
+		switch {
+		case strings.Contains(t, ":"):
+			pass.Reportf(object.Pos(), "exception type %q contains a colon: missing space in the %q separator?", t, separator)
+			continue
+		case strings.HasPrefix(message, ":") || strings.HasPrefix(message, " "):
+			pass.Reportf(object.Pos(), "exception %q has a doubled %q separator", value, separator)
+		case t != strings.TrimSpace(t):
+			pass.Reportf(object.Pos(), "exception type %q has surrounding spaces", t)
+			continue
+		case isTemplate && strings.Contains(t, "%"):
+			pass.Reportf(object.Pos(), "exception type %q depends on the template parameters", t)
+			continue
+		case !pattern.MatchString(t):
+			pass.Reportf(object.Pos(), "exception type %q does not match %s", t, typePattern)
+			continue
+		}
+		if other, ok := declared.Types[t]; ok {
+			pass.Reportf(object.Pos(), "exception type %q is also declared by %s", t, other)
+			continue
+		}
+		if other, ok := imported[t]; ok {
+			pass.Reportf(object.Pos(), "exception type %q is also declared by %s", t, other)
+		}
+		declared.Types[t] = pass.Pkg.Path() + "." + object.Name()
+	}
+	if len(declared.Types) > 0 {
+		pass.ExportPackageFact(declared)
+	}
+	return nil, nil
+}
+
+// exceptionConstants returns the exception.String and exception.Template
+// constants of the package scope, in the order of their declarations.
+func exceptionConstants(scope *types.Scope) []*types.Const {
+	var constants []*types.Const
+	for _, name := range scope.Names() {
+		object, ok := scope.Lookup(name).(*types.Const)
+		if ok && object.Val().Kind() == constant.String &&
+			(isNamed(object.Type(), "String") || isNamed(object.Type(), "Template")) {
+			constants = append(constants, object)
+		}
+	}
+	slices.SortFunc(constants, func(a, b *types.Const) int {
+		return cmp.Compare(a.Pos(), b.Pos())
+	})
+	return constants
+}
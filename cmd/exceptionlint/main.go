@@ -0,0 +1,21 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+// Command exceptionlint reports misuses of the exception package that compile
+// but silently do nothing, and malformed exception constants. It is meant to be
+// run by go vet:
+//
+//	go build -o exceptionlint github.com/thanhminhmr/go-exception/cmd/exceptionlint
+//	go vet -vettool=$(pwd)/exceptionlint ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(UnusedResultAnalyzer, ConstantAnalyzer)
+}
@@ -0,0 +1,21 @@
+package constants // want package:`declaredTypes\(Doubled, Duplicate, File, IOError\)`
+
+import (
+	_ "constdep"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+const (
+	IOError       = exception.String("IOError: read failed")
+	WriteError    = exception.String("IOError: write failed") // want `exception type "IOError" is also declared by constants.IOError`
+	TypeOnly      = exception.String("IOError")               // want `exception type "IOError" is also declared by constants.IOError`
+	FileError     = exception.Template("File: %s failed")
+	MissingSpace  = exception.String("IOError:read failed")       // want `exception type "IOError:read failed" contains a colon: missing space in the ": " separator\?`
+	Doubled       = exception.String("Doubled: : read failed")    // want `exception "Doubled: : read failed" has a doubled ": " separator`
+	Spaced        = exception.String(" IOError: read failed")     // want `exception type " IOError" has surrounding spaces`
+	Parameterized = exception.Template("%s: failed")              // want `exception type "%s" depends on the template parameters`
+	BadName       = exception.String("404: not found")            // want `exception type "404" does not match`
+	Duplicate     = exception.String("Duplicate: declared again") // want `exception type "Duplicate" is also declared by constdep.DuplicateError`
+	NotException  = "IOError:read failed"
+)
@@ -0,0 +1,5 @@
+package constdep
+
+import "github.com/thanhminhmr/go-exception"
+
+const DuplicateError = exception.String("Duplicate: declared here first")
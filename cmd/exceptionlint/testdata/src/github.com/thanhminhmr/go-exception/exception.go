@@ -0,0 +1,27 @@
+// Package exception is a minimal stand-in for the exception package.
+package exception
+
+type Exception interface {
+	Error() string
+	SetMessage(message string, parameters ...any) Exception
+	AddCause(errors ...error) Exception
+	AddSuppressed(errors ...error) Exception
+	SetRecovered(recovered any) Exception
+	FillStackTrace(skip int) Exception
+	GetCause() []error
+}
+
+type String string
+
+func (e String) Error() string                                          { return string(e) }
+func (e String) SetMessage(message string, parameters ...any) Exception { return e }
+func (e String) AddCause(errors ...error) Exception                     { return e }
+func (e String) AddSuppressed(errors ...error) Exception                { return e }
+func (e String) SetRecovered(recovered any) Exception                   { return e }
+func (e String) FillStackTrace(skip int) Exception                      { return e }
+func (e String) GetCause() []error                                      { return nil }
+
+type Template string
+
+func (t Template) Format(parameters ...any) String  { return String(t) }
+func (t Template) Wrap(parameters ...any) Exception { return String(t) }
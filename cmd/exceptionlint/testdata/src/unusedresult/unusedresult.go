@@ -0,0 +1,34 @@
+package unusedresult
+
+import (
+	"errors"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+const IOError = exception.String("IOError: read failed")
+
+const FileError = exception.Template("IOError: %s failed at %d%%")
+
+func discarded(err exception.Exception, cause error) {
+	err.AddCause(cause)        // want `result of AddCause call not used`
+	IOError.SetMessage("x")    // want `result of SetMessage call not used`
+	(err.AddSuppressed(cause)) // want `result of AddSuppressed call not used`
+	err.SetRecovered(nil)      // want `result of SetRecovered call not used`
+	err.FillStackTrace(0)      // want `result of FillStackTrace call not used`
+	_ = err.AddCause(cause)    // explicitly discarded
+	err = err.AddCause(cause)  // used
+	err.GetCause()             // not a modifying method
+	errors.New("x").Error()    // not an exception
+	defer func() { _ = err }()
+}
+
+func formats(name string, parameters []any) {
+	_ = FileError.Format(name, 10)
+	_ = FileError.Format(name)                     // want `Template.Format call has 1 argument but template "IOError: %s failed at %d%%" needs 2`
+	_ = FileError.Format(name, 10, 20)             // want `Template.Format call has 3 arguments but template "IOError: %s failed at %d%%" needs 2`
+	_ = FileError.Format(parameters...)            // not counted
+	_ = FileError.Wrap(name)                       // want `Template.Wrap call has 1 argument but template "IOError: %s failed at %d%%" needs 2`
+	_ = exception.Template("%[1]s %[1]s").Format() // explicit indexes are not counted
+	_ = exception.Template("%*d").Format(5, 10)
+}
@@ -0,0 +1,106 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+
+	"github.com/thanhminhmr/go-exception/internal/verbs"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// exceptionPackage is the import path of the exception package.
+const exceptionPackage = "github.com/thanhminhmr/go-exception"
+
+// UnusedResultAnalyzer reports calls to the methods of an exception that may
+// return a modified copy, whose result is not used, and calls to
+// Template.Format or Template.Wrap whose argument count does not match the
+// template.
+var UnusedResultAnalyzer = &analysis.Analyzer{
+	Name: "exceptionresult",
+	Doc: "report unused results of Exception methods and mismatched Template.Format and Template.Wrap calls\n\n" +
+		"Methods such as AddCause may modify the current exception or return a new one, " +
+		"so calling them as a statement may silently do nothing.",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runUnusedResult,
+}
+
+// modifyingMethods lists the methods of Exception whose result must be used.
+var modifyingMethods = map[string]bool{
+	"SetMessage":     true,
+	"AddCause":       true,
+	"AddSuppressed":  true,
+	"SetRecovered":   true,
+	"FillStackTrace": true,
+}
+
+func runUnusedResult(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.ExprStmt)(nil), (*ast.CallExpr)(nil)}, func(node ast.Node) {
+		switch node := node.(type) {
+		case *ast.ExprStmt:
+			if call, ok := ast.Unparen(node.X).(*ast.CallExpr); ok {
+				checkUnusedResult(pass, call)
+			}
+		case *ast.CallExpr:
+			checkTemplateFormat(pass, node)
+		}
+	})
+	return nil, nil
+}
+
+// checkUnusedResult reports a call statement to a modifying method returning an
+// Exception.
+func checkUnusedResult(pass *analysis.Pass, call *ast.CallExpr) {
+	function, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || !modifyingMethods[function.Name()] {
+		return
+	}
+	signature := function.Type().(*types.Signature)
+	if signature.Recv() == nil || signature.Results().Len() != 1 || !isNamed(signature.Results().At(0).Type(), "Exception") {
+		return
+	}
+	pass.ReportRangef(call, "result of %s call not used: it may return a new Exception", function.Name())
+}
+
+// checkTemplateFormat reports a call to Template.Format or Template.Wrap on a
+// constant template whose argument count does not match the verbs of the
+// template.
+func checkTemplateFormat(pass *analysis.Pass, call *ast.CallExpr) {
+	selector, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Format" && selector.Sel.Name != "Wrap" || call.Ellipsis.IsValid() {
+		return
+	}
+	receiver, ok := pass.TypesInfo.Types[selector.X]
+	if !ok || receiver.Value == nil || receiver.Value.Kind() != constant.String || !isNamed(receiver.Type, "Template") {
+		return
+	}
+	expected, ok := verbs.Count(constant.StringVal(receiver.Value))
+	if ok && expected != len(call.Args) {
+		pass.ReportRangef(call, "Template.%s call has %d %s but template %s needs %d", selector.Sel.Name,
+			len(call.Args), plural(len(call.Args), "argument"), strconv.Quote(constant.StringVal(receiver.Value)), expected)
+	}
+}
+
+// isNamed reports whether the type is the named type of the exception package.
+func isNamed(t types.Type, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Name() == name && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == exceptionPackage
+}
+
+func plural(count int, word string) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,145 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around each change.
+const diffContext = 3
+
+// edit is a line of a diff, prefixed by ' ', '-' or '+'.
+type edit struct {
+	operation byte
+	line      []byte
+}
+
+// Diff returns the unified diff between two versions of a file, or nil if they
+// are identical.
+func Diff(name string, old []byte, new []byte) []byte {
+	if bytes.Equal(old, new) {
+		return nil
+	}
+	edits := diffLines(splitLines(old), splitLines(new))
+	var buffer bytes.Buffer
+	name = strings.TrimPrefix(name, "/")
+	fmt.Fprintf(&buffer, "--- a/%s\n+++ b/%s\n", name, name)
+	// line numbers, starting at 1, of the current edit in both versions
+	oldLine, newLine := 1, 1
+	for start := 0; start < len(edits); {
+		// skips unchanged lines outside of the context of a change
+		first := slices.IndexFunc(edits[start:], func(e edit) bool { return e.operation != ' ' })
+		if first < 0 {
+			break
+		}
+		skip := max(first-diffContext, 0)
+		oldLine, newLine, start = oldLine+skip, newLine+skip, start+skip
+		// extends the hunk while the next change is close enough
+		end, unchanged := start, 0
+		for ; end < len(edits) && unchanged <= 2*diffContext; end++ {
+			if edits[end].operation == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		end -= max(unchanged-diffContext, 0)
+		oldCount, newCount := 0, 0
+		for _, e := range edits[start:end] {
+			if e.operation != '+' {
+				oldCount++
+			}
+			if e.operation != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&buffer, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, e := range edits[start:end] {
+			buffer.WriteByte(e.operation)
+			buffer.Write(e.line)
+			if !bytes.HasSuffix(e.line, []byte("\n")) {
+				buffer.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		oldLine, newLine, start = oldLine+oldCount, newLine+newCount, end
+	}
+	return buffer.Bytes()
+}
+
+// splitLines splits the text into lines, keeping their line endings.
+func splitLines(text []byte) [][]byte {
+	var lines [][]byte
+	for len(text) > 0 {
+		end := bytes.IndexByte(text, '\n') + 1
+		if end == 0 {
+			end = len(text)
+		}
+		lines, text = append(lines, text[:end]), text[end:]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit script turning a into b, using the
+// algorithm of Myers.
+func diffLines(a [][]byte, b [][]byte) []edit {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && bytes.Equal(a[x], b[y]) {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+	// walks the trace backwards from the end of both texts
+	var edits []edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		previous := k - 1
+		if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+			previous = k + 1
+		}
+		previousX := v[offset+previous]
+		previousY := previousX - previous
+		for x > previousX && y > previousY {
+			x, y = x-1, y-1
+			edits = append(edits, edit{' ', a[x]})
+		}
+		if d > 0 {
+			if x == previousX {
+				y--
+				edits = append(edits, edit{'+', b[y]})
+			} else {
+				x--
+				edits = append(edits, edit{'-', a[x]})
+			}
+		}
+	}
+	slices.Reverse(edits)
+	return edits
+}
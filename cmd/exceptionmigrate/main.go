@@ -0,0 +1,153 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+// Command exceptionmigrate rewrites errors.New, fmt.Errorf and errors.Join
+// calls into constructs of the exception package, keeping the formatting and
+// the comments of the files:
+//
+//	exceptionmigrate ./...         # prints the changes as a unified diff
+//	exceptionmigrate -w ./...      # rewrites the files
+//
+// Package-level variables initialized with errors.New on a string literal
+// become [exception.String] constants, unless they are assigned or addressed
+// in the package. fmt.Errorf calls become [exception.String] values without
+// parameters. With parameters, a format starting with a constant type becomes
+// an [exception.Template], formatted by [exception.Template.Wrap] with the
+// same message as fmt.Errorf and the %w parameters as causes. Calls that
+// cannot be rewritten faithfully are reported on the standard error and left
+// untouched.
+//
+// Each argument is a Go file or a directory, a trailing "/..." including its
+// subdirectories except vendor, testdata and hidden ones. The files of a
+// directory are migrated together, so that variables modified in another
+// file are kept.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+// UsageError is the type of the errors caused by invalid command line
+// arguments.
+const UsageError = exception.String("UsageError")
+
+// MigrateError is the type of the errors found while reading or writing the
+// files.
+const MigrateError = exception.String("MigrateError")
+
+func main() {
+	program := exception.Program{ExitCodes: map[string]int{
+		UsageError.GetType():   exception.ExitUsage,
+		MigrateError.GetType(): exception.ExitIOError,
+	}}
+	os.Exit(program.Run(run))
+}
+
+func run(context.Context) error {
+	write := flag.Bool("w", false, "rewrite the files instead of printing a diff")
+	flag.Usage = func() {
+		_, _ = flag.CommandLine.Output().Write([]byte("usage: exceptionmigrate [-w] path...\n"))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		return UsageError + ": expected at least one path"
+	}
+	directories := map[string][]string{}
+	for _, argument := range flag.Args() {
+		if err := collect(directories, argument); err != nil {
+			return err
+		}
+	}
+	var errs []error
+	for _, directory := range slices.Sorted(maps.Keys(directories)) {
+		if err := migrateDirectory(directories[directory], *write); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return exception.Join(errs...)
+}
+
+// collect adds the Go files found at the path to their directories.
+func collect(directories map[string][]string, path string) error {
+	root, recursive := strings.CutSuffix(path, "/...")
+	if root == "" {
+		root = "."
+	}
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return MigrateError.AddCause(err)
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != root && (!recursive || name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == root || strings.HasSuffix(name, ".go") {
+			directory := filepath.Dir(path)
+			if !slices.Contains(directories[directory], path) {
+				directories[directory] = append(directories[directory], path)
+			}
+		}
+		return nil
+	})
+}
+
+// migrateDirectory migrates the files of a directory, then prints the diff or
+// rewrites the files.
+func migrateDirectory(paths []string, write bool) error {
+	fileSet := token.NewFileSet()
+	sources := map[*ast.File][]byte{}
+	names := map[*ast.File]string{}
+	var files []*ast.File
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return MigrateError.AddCause(err)
+		}
+		file, err := parser.ParseFile(fileSet, path, source, parser.ParseComments)
+		if err != nil {
+			return MigrateError.AddCause(err)
+		}
+		files = append(files, file)
+		sources[file], names[file] = source, path
+	}
+	changed, warnings := Migrate(fileSet, files)
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintln(os.Stderr, warning)
+	}
+	for _, file := range changed {
+		result, err := formatFile(fileSet, file)
+		if err != nil {
+			return MigrateError.AddCause(err)
+		}
+		path := names[file]
+		if write {
+			if err := os.WriteFile(path, result, 0o644); err != nil {
+				return MigrateError.AddCause(err)
+			}
+		} else if _, err := os.Stdout.Write(Diff(filepath.ToSlash(path), sources[file], result)); err != nil {
+			return MigrateError.AddCause(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/thanhminhmr/go-exception/internal/verbs"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/imports"
+)
+
+// exceptionPackage is the import path of the exception package.
+const exceptionPackage = "github.com/thanhminhmr/go-exception"
+
+// separator between type and message, as used by exception.String
+const separator = ": "
+
+// Migrate rewrites the files of a package in place and returns the warnings
+// about the calls it left untouched:
+//
+//   - errors.New on a string literal becomes exception.String, unless the
+//     message contains the ": " separator of exception.String, and
+//     package-level variables initialized that way become constants, unless
+//     they are assigned or addressed somewhere in the package.
+//   - fmt.Errorf becomes exception.String without parameters, under the same
+//     condition as errors.New. Otherwise, if the format starts with a constant
+//     type followed by the separator, it becomes the Wrap method of an
+//     exception.Template, which keeps the same message and attaches the %w
+//     parameters as causes, or its Format method without %w verbs.
+//   - errors.Join becomes exception.Join.
+func Migrate(fileSet *token.FileSet, files []*ast.File) (changed []*ast.File, warnings []string) {
+	assigned := map[string]bool{}
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				if node.Tok != token.DEFINE {
+					for _, left := range node.Lhs {
+						markAssigned(assigned, left)
+					}
+				}
+			case *ast.IncDecStmt:
+				markAssigned(assigned, node.X)
+			case *ast.UnaryExpr:
+				if node.Op == token.AND {
+					markAssigned(assigned, node.X)
+				}
+			}
+			return true
+		})
+	}
+	for _, file := range files {
+		m := &migrator{
+			fileSet:    fileSet,
+			file:       file,
+			errorsName: importName(file, "errors"),
+			fmtName:    importName(file, "fmt"),
+			assigned:   assigned,
+		}
+		if m.errorsName == "" && m.fmtName == "" {
+			continue
+		}
+		m.migrate()
+		if m.changed {
+			changed = append(changed, file)
+		}
+		warnings = append(warnings, m.warnings...)
+	}
+	return changed, warnings
+}
+
+func markAssigned(assigned map[string]bool, expression ast.Expr) {
+	if identifier, ok := ast.Unparen(expression).(*ast.Ident); ok {
+		assigned[identifier.Name] = true
+	}
+}
+
+// importName returns the name under which the file imports the package, or
+// an empty string if it does not import it by name.
+func importName(file *ast.File, path string) string {
+	for _, spec := range file.Imports {
+		if value, _ := strconv.Unquote(spec.Path.Value); value != path {
+			continue
+		}
+		if spec.Name == nil {
+			return path
+		}
+		if spec.Name.Name != "_" && spec.Name.Name != "." {
+			return spec.Name.Name
+		}
+	}
+	return ""
+}
+
+type migrator struct {
+	fileSet    *token.FileSet
+	file       *ast.File
+	errorsName string
+	fmtName    string
+	assigned   map[string]bool
+	changed    bool
+	warnings   []string
+}
+
+func (m *migrator) migrate() {
+	for _, declaration := range m.file.Decls {
+		if group, ok := declaration.(*ast.GenDecl); ok && m.isSentinelGroup(group) {
+			group.Tok = token.CONST
+		}
+	}
+	// keeps the error type of the variables initialized by a rewritten call
+	ast.Inspect(m.file, func(node ast.Node) bool {
+		if group, ok := node.(*ast.GenDecl); ok && group.Tok == token.VAR {
+			for _, spec := range group.Specs {
+				value := spec.(*ast.ValueSpec)
+				if value.Type == nil && slices.ContainsFunc(value.Values, func(value ast.Expr) bool {
+					call, ok := value.(*ast.CallExpr)
+					return ok && m.rewritable(call)
+				}) {
+					value.Type = &ast.Ident{Name: "error", NamePos: value.Names[len(value.Names)-1].End()}
+				}
+			}
+		}
+		return true
+	})
+	astutil.Apply(m.file, nil, func(cursor *astutil.Cursor) bool {
+		call, ok := cursor.Node().(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		function := m.function(call)
+		if function == "" {
+			return true
+		}
+		if assign, ok := cursor.Parent().(*ast.AssignStmt); ok && assign.Tok == token.DEFINE {
+			m.warnf(call, "%s not rewritten: the type of the defined variable would change", function)
+			return true
+		}
+		replacement, reason := m.rewrite(call, function)
+		if reason != "" {
+			m.warnf(call, "%s not rewritten: %s", function, reason)
+		} else {
+			cursor.Replace(replacement)
+			m.changed = true
+		}
+		return true
+	})
+	if !m.changed {
+		return
+	}
+	astutil.AddImport(m.fileSet, m.file, exceptionPackage)
+	for _, path := range []string{"errors", "fmt"} {
+		if name := importName(m.file, path); name != "" && !astutil.UsesImport(m.file, path) {
+			if name == path {
+				astutil.DeleteImport(m.fileSet, m.file, path)
+			} else {
+				astutil.DeleteNamedImport(m.fileSet, m.file, name, path)
+			}
+		}
+	}
+}
+
+// isSentinelGroup reports whether the declaration only declares variables
+// initialized with errors.New on a string literal, that are never modified.
+func (m *migrator) isSentinelGroup(group *ast.GenDecl) bool {
+	if group.Tok != token.VAR || len(group.Specs) == 0 {
+		return false
+	}
+	for _, spec := range group.Specs {
+		value := spec.(*ast.ValueSpec)
+		if value.Type != nil || len(value.Names) != 1 || len(value.Values) != 1 || m.assigned[value.Names[0].Name] {
+			return false
+		}
+		call, ok := value.Values[0].(*ast.CallExpr)
+		if !ok || !m.isCall(call, m.errorsName, "New") || !m.rewritable(call) {
+			return false
+		}
+	}
+	return true
+}
+
+// isCall reports whether the call is a call to the function of the imported
+// package.
+func (m *migrator) isCall(call *ast.CallExpr, packageName string, function string) bool {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || packageName == "" || selector.Sel.Name != function {
+		return false
+	}
+	identifier, ok := selector.X.(*ast.Ident)
+	// an unresolved identifier is a package name
+	return ok && identifier.Name == packageName && identifier.Obj == nil
+}
+
+// function returns the name of the rewritten function called, or an empty
+// string for other calls.
+func (m *migrator) function(call *ast.CallExpr) string {
+	switch {
+	case m.isCall(call, m.errorsName, "New"):
+		return "errors.New"
+	case m.isCall(call, m.errorsName, "Join"):
+		return "errors.Join"
+	case m.isCall(call, m.fmtName, "Errorf"):
+		return "fmt.Errorf"
+	default:
+		return ""
+	}
+}
+
+// rewritable reports whether the call is a call to a rewritten function that
+// can be rewritten faithfully.
+func (m *migrator) rewritable(call *ast.CallExpr) bool {
+	function := m.function(call)
+	if function == "" {
+		return false
+	}
+	_, reason := m.rewrite(call, function)
+	return reason == ""
+}
+
+// rewrite returns the replacement of the call, or the reason why it is kept.
+func (m *migrator) rewrite(call *ast.CallExpr, function string) (ast.Expr, string) {
+	switch function {
+	case "errors.New":
+		if len(call.Args) != 1 || call.Ellipsis.IsValid() {
+			return nil, "invalid parameters"
+		}
+		literal, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			return nil, "the message is not a string literal"
+		}
+		message, err := strconv.Unquote(literal.Value)
+		if err != nil {
+			return nil, err.Error()
+		}
+		return m.string(call, literal, message)
+	case "errors.Join":
+		join := m.call(call, "Join", call.Args...)
+		join.Ellipsis = call.Ellipsis
+		return join, ""
+	default:
+		return m.errorf(call)
+	}
+}
+
+func (m *migrator) errorf(call *ast.CallExpr) (ast.Expr, string) {
+	if len(call.Args) == 0 {
+		return nil, "invalid parameters"
+	}
+	literal, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return nil, "the format is not a string literal"
+	}
+	if call.Ellipsis.IsValid() {
+		return nil, "the parameters are passed as a slice"
+	}
+	format, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return nil, err.Error()
+	}
+	parameters := call.Args[1:]
+	if count, ok := verbs.Count(format); ok && count != len(parameters) {
+		return nil, fmt.Sprintf("the format consumes %d parameters but %d are given", count, len(parameters))
+	}
+	if len(parameters) == 0 {
+		return m.string(call, literal, strings.ReplaceAll(format, "%%", "%"))
+	}
+	// the type of the exception is the part of the message before the
+	// separator, which must not depend on the parameters
+	if prefix, _, ok := strings.Cut(format, separator); !ok || prefix == "" || strings.Contains(prefix, "%") {
+		return nil, fmt.Sprintf("the format does not start with a constant type followed by %q", separator)
+	}
+	template := m.call(call, "Template", literal)
+	if slices.ContainsFunc(verbs.Parse(format), func(verb verbs.Verb) bool { return verb.Character == 'w' }) {
+		// Wrap keeps the message of fmt.Errorf and attaches the %w causes
+		return m.method(call, template, "Wrap", parameters...), ""
+	}
+	return m.method(call, template, "Format", parameters...), ""
+}
+
+// string returns a call to exception.String with the message, or the reason
+// why it is kept.
+func (m *migrator) string(call *ast.CallExpr, literal *ast.BasicLit, message string) (ast.Expr, string) {
+	if strings.Contains(message, separator) {
+		// the prefix would become the type, matching other errors with the
+		// same prefix under errors.Is
+		return nil, fmt.Sprintf("the message contains %q, which exception.String takes as the end of its type", separator)
+	}
+	return m.call(call, "String", m.literal(literal, message)), ""
+}
+
+// call returns a call to the function of the exception package, at the
+// position of the replaced call.
+func (m *migrator) call(replaced *ast.CallExpr, function string, parameters ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{
+			X:   ast.NewIdent("exception"),
+			Sel: ast.NewIdent(function),
+		},
+		Lparen: replaced.Lparen,
+		Args:   parameters,
+		Rparen: replaced.Rparen,
+	}
+}
+
+// method returns a call to the method on the receiver, at the position of the
+// replaced call.
+func (m *migrator) method(replaced *ast.CallExpr, receiver ast.Expr, method string, parameters ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{
+		Fun:    &ast.SelectorExpr{X: receiver, Sel: ast.NewIdent(method)},
+		Lparen: replaced.Lparen,
+		Args:   parameters,
+		Rparen: replaced.Rparen,
+	}
+}
+
+// literal returns the literal itself if its value is unchanged, keeping its
+// original quoting, otherwise a new literal with the value.
+func (m *migrator) literal(literal *ast.BasicLit, value string) *ast.BasicLit {
+	if original, _ := strconv.Unquote(literal.Value); original == value {
+		return literal
+	}
+	return &ast.BasicLit{ValuePos: literal.ValuePos, Kind: token.STRING, Value: strconv.Quote(value)}
+}
+
+func (m *migrator) warnf(node ast.Node, format string, parameters ...any) {
+	m.warnings = append(m.warnings, m.fileSet.Position(node.Pos()).String()+": "+fmt.Sprintf(format, parameters...))
+}
+
+// ========================================
+
+// formatFile returns the formatted source of the file, with the exception
+// package imported in its own group.
+func formatFile(fileSet *token.FileSet, file *ast.File) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := format.Node(&buffer, fileSet, file); err != nil {
+		return nil, err
+	}
+	return imports.Process("", buffer.Bytes(), &imports.Options{FormatOnly: true, Comments: true, TabIndent: true, TabWidth: 8})
+}
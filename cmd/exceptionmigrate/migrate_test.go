@@ -0,0 +1,89 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestMigrate(t *testing.T) {
+	fileSet := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range []string{"legacy.go", "legacy_replace.go"} {
+		file, err := parser.ParseFile(fileSet, filepath.Join("testdata", "legacy", name), nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	changed, warnings := Migrate(fileSet, files)
+	if len(changed) != 1 || changed[0] != files[0] {
+		t.Fatalf("Expected only legacy.go to change but got %d files", len(changed))
+	}
+	expected := []string{
+		`testdata/legacy/legacy.go:18:17: errors.New not rewritten: the message contains ": ", which exception.String takes as the end of its type`,
+		`testdata/legacy/legacy.go:31:10: fmt.Errorf not rewritten: the format does not start with a constant type followed by ": "`,
+		`testdata/legacy/legacy.go:38:10: fmt.Errorf not rewritten: the format does not start with a constant type followed by ": "`,
+		`testdata/legacy/legacy.go:41:10: fmt.Errorf not rewritten: the message contains ": ", which exception.String takes as the end of its type`,
+		`testdata/legacy/legacy.go:55:10: fmt.Errorf not rewritten: the format does not start with a constant type followed by ": "`,
+		"testdata/legacy/legacy.go:57:9: fmt.Errorf not rewritten: the format is not a string literal",
+		"testdata/legacy/legacy.go:61:9: errors.New not rewritten: the type of the defined variable would change",
+	}
+	if !slices.Equal(warnings, expected) {
+		t.Errorf("Expected warnings %q but got %q", expected, warnings)
+	}
+	result, err := formatFile(fileSet, files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden(t, filepath.Join("testdata", "legacy.go.golden"), result)
+	source, err := os.ReadFile(filepath.Join("testdata", "legacy", "legacy.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden(t, filepath.Join("testdata", "legacy.diff.golden"), Diff("legacy.go", source, result))
+}
+
+func golden(t *testing.T, path string, actual []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("Output differs from %s, run the tests with -update to see the difference:\n%s", path, actual)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := []byte("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n")
+	new := []byte("a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n")
+	expected := "--- a/x\n+++ b/x\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -11,3 +11,4 @@\n k\n l\n m\n+n\n"
+	if actual := string(Diff("x", old, new)); actual != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, actual)
+	}
+	if Diff("x", old, old) != nil {
+		t.Errorf("Expected no diff for identical files")
+	}
+}
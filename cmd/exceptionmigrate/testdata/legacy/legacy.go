@@ -0,0 +1,67 @@
+// Package legacy uses the standard errors for the migration tests.
+package legacy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Sentinel errors of the package.
+var (
+	// ErrNotFound is returned for missing entries.
+	ErrNotFound = errors.New("not found")
+	ErrClosed   = errors.New("closed") // returned after Close
+)
+
+// ErrLegacy has a prefix that exception.String would take as its type.
+var ErrLegacy = errors.New("legacy: failed")
+
+// ErrReplaced is replaced by tests, so it stays a variable.
+var ErrReplaced = errors.New("replaced")
+
+var errTyped error = errors.New("typed")
+
+func Open(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+	if _, err := os.Stat(name); err != nil {
+		// wraps the cause, but the type depends on the parameters
+		return fmt.Errorf("open %q: %w", name, err)
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("name too long: %d%%", len(name))
+	}
+	if name[0] == '.' {
+		// the prefix depends on the parameters
+		return fmt.Errorf("%s: hidden file", name)
+	}
+	if name[0] == '-' {
+		return fmt.Errorf("legacy: invalid name")
+	}
+	return fmt.Errorf("100%% done")
+}
+
+func Close(first, second error) error {
+	if first == nil {
+		return fmt.Errorf("close failed: %w", second)
+	}
+	return errors.Join(first, second, ErrClosed)
+}
+
+func Untouched(err error, format string) error {
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("%w: wrapped first", err)
+	}
+	return fmt.Errorf(format, err)
+}
+
+func Defined() error {
+	err := errors.New("defined")
+	var declared = fmt.Errorf("declared")
+	if declared != nil {
+		err = declared
+	}
+	return err
+}
@@ -0,0 +1,230 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+)
+
+// CrashReportError is the type of the errors returned while reading or writing
+// crash reports.
+const CrashReportError = String("CrashReportError")
+
+// crash report files are named crashPrefix + ID + crashSuffix, so that they sort
+// by creation time
+const (
+	crashPrefix = "crash-"
+	crashSuffix = ".log"
+)
+
+// CrashReporter writes crash reports into a directory, keeping only the most
+// recent ones.
+//
+// Panics escaping every handler are written by the runtime itself, through
+// [debug.SetCrashOutput], in addition to the standard error. Panics caught by a
+// recover handler can be written in the same format with
+// [CrashReporter.Report]. Every report can later be read back with
+// [ReadCrashReports].
+type CrashReporter struct {
+	directory string
+	keep      int
+	output    string
+}
+
+// StartCrashReporter creates the directory if needed, removes empty and
+// outdated crash reports so that at most keep reports remain, and directs the
+// crash output of the runtime to a new report file in that directory.
+//
+// Only one crash reporter should be started per process, as the runtime only
+// has one crash output.
+func StartCrashReporter(directory string, keep int) (reporter *CrashReporter, err error) {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, CrashReportError.AddCause(err)
+	}
+	reporter = &CrashReporter{directory: directory, keep: keep}
+	if err := reporter.rotate(); err != nil {
+		return nil, err
+	}
+	reporter.output = reporter.path(NewID())
+	file, err := os.Create(reporter.output)
+	if err != nil {
+		return nil, CrashReportError.AddCause(err)
+	}
+	defer Close(&err, file)
+	// the runtime keeps its own duplicate of the file descriptor
+	if err := debug.SetCrashOutput(file, debug.CrashOptions{}); err != nil {
+		return nil, CrashReportError.AddCause(err)
+	}
+	return reporter, nil
+}
+
+// Stop stops directing the crash output of the runtime to the report file.
+func (r *CrashReporter) Stop() error {
+	if err := debug.SetCrashOutput(nil, debug.CrashOptions{}); err != nil {
+		return CrashReportError.AddCause(err)
+	}
+	return nil
+}
+
+// Report writes a crash report for an exception, usually one returned by
+// [Recover], in the same format as the crash output of the runtime. Only the
+// recovered value, or the error string if there is none, the stack trace and
+// the goroutine snapshot of the exception are written. The active
+// [RedactionPolicy] is applied.
+func (r *CrashReporter) Report(err Exception) (result error) {
+	id := err.GetID()
+	if id == "" {
+		id = NewID()
+	}
+	file, createErr := os.Create(r.path(id))
+	if createErr != nil {
+		return CrashReportError.AddCause(createErr)
+	}
+	defer Close(&result, file)
+	if writeErr := writeCrash(file, err); writeErr != nil {
+		return CrashReportError.AddCause(writeErr)
+	}
+	return r.rotate()
+}
+
+func (r *CrashReporter) path(id string) string {
+	return filepath.Join(r.directory, crashPrefix+id+crashSuffix)
+}
+
+// rotate removes empty crash reports, left by processes that did not crash, and
+// the oldest ones beyond the limit. The crash output of this process is kept.
+func (r *CrashReporter) rotate() error {
+	paths, err := crashReportPaths(r.directory)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	kept := paths[:0]
+	for _, path := range paths {
+		if path == r.output {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.Size() == 0 {
+			errs = append(errs, os.Remove(path))
+		} else {
+			kept = append(kept, path)
+		}
+	}
+	for len(kept) > r.keep {
+		errs = append(errs, os.Remove(kept[0]))
+		kept = kept[1:]
+	}
+	if err := Join(errs...); err != nil {
+		return CrashReportError.AddCause(err)
+	}
+	return nil
+}
+
+// writeCrash writes the exception in the format of the crash output of the
+// runtime.
+func writeCrash(writer io.Writer, err Exception) error {
+	policy := GetRedactionPolicy()
+	message := redactedErrorString(policy, err)
+	if recovered := err.GetRecovered(); recovered != nil {
+		if policy.IsMasked("recovered") {
+			message = policy.mask()
+		} else {
+			message = fmt.Sprint(policy.RedactRecovered(recovered))
+		}
+	}
+	buffer := bufio.NewWriter(writer)
+	_, _ = fmt.Fprintf(buffer, "panic: %s [recovered]\n\ngoroutine 0 [running]:\n", message)
+	writeCrashTrace(buffer, err.GetStackTrace())
+	for _, goroutine := range err.GetGoroutines() {
+		_, _ = fmt.Fprintf(buffer, "\ngoroutine %d [%s", goroutine.ID, goroutine.State)
+		if goroutine.Wait != 0 {
+			_, _ = fmt.Fprintf(buffer, ", %d minutes", int(goroutine.Wait.Minutes()))
+		}
+		_, _ = fmt.Fprintf(buffer, "]:\n")
+		writeCrashTrace(buffer, goroutine.StackTrace)
+		if frame := goroutine.CreatedBy; frame.Function != "" {
+			_, _ = fmt.Fprintf(buffer, "created by %s in goroutine %d\n\t%s:%d\n", frame.Function, goroutine.CreatorID, frame.File, frame.Line)
+		}
+	}
+	return buffer.Flush()
+}
+
+func writeCrashTrace(writer io.Writer, trace StackFrames) {
+	for _, frame := range trace {
+		_, _ = fmt.Fprintf(writer, "%s(...)\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+}
+
+// ========================================
+
+// CrashReport is a crash report read back by [ReadCrashReports].
+type CrashReport struct {
+	// Path is the path of the report file.
+	Path string
+
+	// Exception is the panic found in the report, using [PanicError] as its type
+	// and the panic message as its recovered value. Its ID comes from the name of
+	// the report file and its time from the modification time of the file. The
+	// other goroutines found in the report, if any, are its goroutine snapshot.
+	Exception Exception
+}
+
+// ReadCrashReports reads the crash reports found in the directory, oldest
+// first. Empty reports, left by processes that did not crash, are skipped.
+func ReadCrashReports(directory string) ([]CrashReport, error) {
+	paths, err := crashReportPaths(directory)
+	if err != nil {
+		return nil, err
+	}
+	var reports []CrashReport
+	for _, path := range paths {
+		exception, err := readCrashReport(path)
+		if err != nil {
+			return reports, err
+		}
+		if exception != nil {
+			reports = append(reports, CrashReport{Path: path, Exception: exception})
+		}
+	}
+	return reports, nil
+}
+
+func crashReportPaths(directory string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(directory, crashPrefix+"*"+crashSuffix))
+	if err != nil {
+		return nil, CrashReportError.AddCause(err)
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+func readCrashReport(path string) (result Exception, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, CrashReportError.AddCause(err)
+	}
+	defer Close(&err, file)
+	info, err := file.Stat()
+	if err != nil {
+		return nil, CrashReportError.AddCause(err)
+	}
+	exception, err := parsePanic(file)
+	if err != nil || exception == nil {
+		return nil, err
+	}
+	full := exception.(fullException)
+	full.ID = strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), crashPrefix), crashSuffix)
+	full.Time = info.ModTime()
+	return full, nil
+}
@@ -0,0 +1,103 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+func TestCrashReporterRecovered(t *testing.T) {
+	// the recovered value is compared unredacted
+	previous := exception.SetRedactionPolicy(nil)
+	defer exception.SetRedactionPolicy(previous)
+	directory := t.TempDir()
+	reporter, err := exception.StartCrashReporter(directory, 2)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	defer reporter.Stop()
+	recovered := recoverFrom(func() { panic("Test") })
+	if err := reporter.Report(recovered); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	reports, err := exception.ReadCrashReports(directory)
+	if err != nil || len(reports) != 1 {
+		t.Fatalf("Expected one report but got %#v and %v", reports, err)
+	}
+	report := reports[0].Exception
+	if report.GetRecovered() != "Test" || report.GetID() != recovered.GetID() {
+		t.Errorf("Expected the recovered panic but got %#v", report)
+	}
+	// program counters are not written in crash reports
+	expected := recovered.GetStackTrace()[0]
+	expected.PC = 0
+	if len(report.GetStackTrace()) != len(recovered.GetStackTrace()) || report.GetStackTrace()[0] != expected {
+		t.Errorf("Expected stack trace %v but got %v", recovered.GetStackTrace(), report.GetStackTrace())
+	}
+}
+
+func TestCrashReporterMaskedRecovered(t *testing.T) {
+	previous := exception.SetRedactionPolicy(&exception.RedactionPolicy{Keys: []string{"recovered"}})
+	defer exception.SetRedactionPolicy(previous)
+	directory := t.TempDir()
+	reporter, err := exception.StartCrashReporter(directory, 2)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	defer reporter.Stop()
+	if err := reporter.Report(recoverFrom(func() { panic("secret") })); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	reports, err := exception.ReadCrashReports(directory)
+	if err != nil || len(reports) != 1 {
+		t.Fatalf("Expected one report but got %#v and %v", reports, err)
+	}
+	if recovered := reports[0].Exception.GetRecovered(); recovered != "[REDACTED]" {
+		t.Errorf("Expected the masked recovered value but got %#v", recovered)
+	}
+}
+
+func TestCrashReporterUnrecovered(t *testing.T) {
+	if directory := os.Getenv("CRASH_REPORT_DIRECTORY"); directory != "" {
+		if _, err := exception.StartCrashReporter(directory, 2); err != nil {
+			t.Fatalf("Expected no error but got %v", err)
+		}
+		func() {
+			defer func() {
+				panic("Second")
+			}()
+			var dictionary map[string]int
+			dictionary["key"] = 1
+		}()
+		return
+	}
+	directory := t.TempDir()
+	command := exec.Command(os.Args[0], "-test.run=^TestCrashReporterUnrecovered$")
+	command.Env = append(os.Environ(), "CRASH_REPORT_DIRECTORY="+directory)
+	if err := command.Run(); err == nil {
+		t.Fatalf("Expected the process to crash")
+	}
+	reports, err := exception.ReadCrashReports(directory)
+	if err != nil || len(reports) != 1 {
+		t.Fatalf("Expected one report but got %#v and %v", reports, err)
+	}
+	report := reports[0].Exception
+	if report.GetRecovered() != "Second" {
+		t.Errorf("Expected the last panic but got %#v", report)
+	}
+	checkStackTrace(t, report.GetStackTrace(), "/go-exception_test.TestCrashReporterUnrecovered.func1.1")
+	suppressed := report.GetSuppressed()
+	if len(suppressed) != 1 || !errors.Is(suppressed[0], exception.NilMapError) {
+		t.Fatalf("Expected the nil map panic to be suppressed but got %#v", suppressed)
+	}
+	checkStackTrace(t, suppressed[0].(exception.Exception).GetStackTrace(), "/go-exception_test.TestCrashReporterUnrecovered.func1")
+}
@@ -0,0 +1,42 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import "io"
+
+// Close closes the closer and records its error like [Defer]. It is meant to be
+// deferred right after a resource is acquired:
+//
+//	func read(name string) (err error) {
+//	    file, err := os.Open(name)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    defer exception.Close(&err, file)
+//	    ...
+//	}
+func Close(err *error, closer io.Closer) {
+	Defer(err, closer.Close)
+}
+
+// Defer calls the function, usually a Flush or a Rollback, and records its
+// error in err.
+//
+// If err is nil, the error of the function becomes the result. Otherwise, the
+// result stays the primary failure and the error of the function is attached
+// to it with [Exception.AddSuppressed]. A foreign error in err is first wrapped
+// into an [Exception] with the same message.
+func Defer(err *error, function func() error) {
+	deferred := function()
+	switch {
+	case deferred == nil: // skip
+	case *err == nil:
+		*err = deferred
+	default:
+		*err = wrap(*err).AddSuppressed(deferred)
+	}
+}
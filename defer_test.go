@@ -0,0 +1,57 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+const CloseError = exception.String("CloseError: close failed")
+
+type failingCloser struct{}
+
+func (failingCloser) Close() error {
+	return CloseError
+}
+
+func TestCloseBecomesResult(t *testing.T) {
+	err := func() (err error) {
+		defer exception.Close(&err, failingCloser{})
+		return nil
+	}()
+	if err != CloseError {
+		t.Errorf("Expected close error to become the result but got %#v", err)
+	}
+}
+
+func TestCloseSuppressed(t *testing.T) {
+	primary := errors.New("primary")
+	err := func() (err error) {
+		defer exception.Close(&err, failingCloser{})
+		return primary
+	}()
+	if !errors.Is(err, primary) || err.Error() != "primary" {
+		t.Fatalf("Expected primary error to stay the result but got %#v", err)
+	}
+	suppressed := err.(exception.Exception).GetSuppressed()
+	if len(suppressed) != 1 || suppressed[0] != CloseError {
+		t.Errorf("Expected close error to be suppressed but got %#v", suppressed)
+	}
+}
+
+func TestDeferWithoutError(t *testing.T) {
+	err := func() (err error) {
+		defer exception.Defer(&err, func() error { return nil })
+		return nil
+	}()
+	if err != nil {
+		t.Errorf("Expected no error but got %#v", err)
+	}
+}
@@ -6,6 +6,8 @@
 
 package exception
 
+import "time"
+
 // Exception defines a lightweight exception model for Go, providing mechanisms
 // for chaining causes, tracking suppressed errors, storing recovered values, and
 // capturing stack traces.
@@ -25,7 +27,10 @@ type Exception interface {
 	// GetMessage returns the message of this exception.
 	GetMessage() string
 
-	// SetMessage stores a message inside this exception.
+	// SetMessage stores a message inside this exception. When parameters are
+	// given, the message is formatted like [fmt.Sprintf], except that %w verbs
+	// are formatted like %v and the errors given to them are attached as causes,
+	// as [fmt.Errorf] would wrap them.
 	//
 	// Note: This method may modify the current exception or return a new one. Always
 	// use the returned [Exception].
@@ -79,5 +84,25 @@ type Exception interface {
 	// use the returned [Exception].
 	FillStackTrace(skip int) Exception
 
+	// GetID returns the unique instance ID of this exception, which identifies a
+	// single occurrence rather than its type. An ID is assigned when the stack
+	// trace is first filled, or when the exception is created by [Panic] or
+	// [Recover], and is kept by every later modification. It returns an empty
+	// string if no ID was assigned.
+	GetID() string
+
+	// GetTime returns the time this exception was created, assigned together with
+	// its ID. It returns the zero time if no time was assigned.
+	//
+	// The time carries a monotonic clock reading, so the offset between two
+	// exceptions created by the same process, such as a primary exception and its
+	// suppressed errors, can be measured with [time.Time.Sub].
+	GetTime() time.Time
+
+	// GetGoroutines returns the snapshot of all goroutines captured when this
+	// exception was recovered, if the [RecoveryPolicy] asked for one. It returns
+	// nil otherwise.
+	GetGoroutines() []Goroutine
+
 	__() // private
 }
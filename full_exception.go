@@ -6,7 +6,10 @@
 
 package exception
 
-import "fmt"
+import (
+	"slices"
+	"time"
+)
 
 // type check
 var _ Exception = fullException{}
@@ -14,14 +17,20 @@ var _ Exception = fullException{}
 type fullException struct {
 	Type       string
 	Message    string
-	Cause      []error
+	Causes     []error
 	Suppressed []error
 	Recovered  any
-	StackTrace []StackFrame
+	Stack      StackFrames
+	ID         string
+	Time       time.Time
+	Goroutines []Goroutine
 }
 
 func (e fullException) Error() string {
 	switch {
+	case e.Type == "" && e.Message == "" && len(e.Causes) > 0:
+		// a wrapper of joined errors, such as a Join with a stack trace
+		return GetJoinStyle()(e.Causes)
 	case e.Type == "":
 		return e.Message
 	case e.Message == "":
@@ -42,18 +51,24 @@ func (e fullException) GetMessage() string {
 func (e fullException) SetMessage(message string, parameters ...any) Exception {
 	if message == "" || len(parameters) == 0 {
 		e.Message = message
-	} else {
-		e.Message = fmt.Sprintf(message, parameters...)
+		return e
+	}
+	var wrapped []error
+	e.Message, wrapped = formatMessage(message, parameters)
+	if len(wrapped) > 0 {
+		return e.AddCause(wrapped...)
 	}
 	return e
 }
 
 func (e fullException) GetCause() []error {
-	return e.Cause
+	return e.Causes
 }
 
 func (e fullException) AddCause(errors ...error) Exception {
-	concat(&e.Cause, errors...)
+	// the copies of this exception share the causes, never append in place
+	e.Causes = slices.Clip(e.Causes)
+	concat(&e.Causes, errors...)
 	return e
 }
 
@@ -62,6 +77,7 @@ func (e fullException) GetSuppressed() []error {
 }
 
 func (e fullException) AddSuppressed(errors ...error) Exception {
+	e.Suppressed = slices.Clip(e.Suppressed)
 	concat(&e.Suppressed, errors...)
 	return e
 }
@@ -76,18 +92,37 @@ func (e fullException) SetRecovered(recovered any) Exception {
 }
 
 func (e fullException) GetStackTrace() StackFrames {
-	return e.StackTrace
+	return e.Stack
 }
 
 func (e fullException) FillStackTrace(skip int) Exception {
-	e.StackTrace = StackTrace(skip + 1)
+	return e.withStackTrace(StackTrace(skip + 1))
+}
+
+func (e fullException) withStackTrace(trace StackFrames) Exception {
+	e.Stack = trace
+	if e.ID == "" {
+		e.ID, e.Time = NewID(), time.Now()
+	}
 	return e
 }
 
+func (e fullException) GetID() string {
+	return e.ID
+}
+
+func (e fullException) GetTime() time.Time {
+	return e.Time
+}
+
+func (e fullException) GetGoroutines() []Goroutine {
+	return e.Goroutines
+}
+
 func (e fullException) __() {}
 
 func (e fullException) Unwrap() []error {
-	return e.Cause
+	return e.Causes
 }
 
 func (e fullException) Is(target error) bool {
@@ -0,0 +1,306 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GoroutineDumpError is the type of the errors returned while reading a
+// goroutine dump.
+const GoroutineDumpError = String("GoroutineDumpError")
+
+// Goroutine is a goroutine found in a goroutine dump by [ParseGoroutineDump].
+type Goroutine struct {
+	// ID is the goroutine ID.
+	ID int
+
+	// State is the state of the goroutine, such as "running" or "chan receive".
+	State string
+
+	// Wait is the approximate time the goroutine has been blocked, as printed by
+	// the runtime with a precision of one minute. It is zero for shorter waits.
+	Wait time.Duration
+
+	// StackTrace is the stack trace of the goroutine, starting from the
+	// innermost frame. Frames whose function is "panic" separate the traces of
+	// nested panics.
+	StackTrace StackFrames
+
+	// CreatedBy is the go statement that created the goroutine. It is the zero
+	// value for the main goroutine or if the dump does not tell.
+	CreatedBy StackFrame
+
+	// CreatorID is the ID of the goroutine that created this one, or 0 if the
+	// dump does not tell.
+	CreatorID int
+
+	// Exception is the panic of this goroutine, using [PanicError] as its type and
+	// the panic message as its recovered value, or nil if this goroutine is not
+	// the one that panicked. Nested panics are attached with
+	// [Exception.AddSuppressed] to the panic that replaced them.
+	Exception Exception
+}
+
+// ParseGoroutineDump parses a goroutine dump, such as the output of a program
+// that crashed with a panic, of a SIGQUIT, of [runtime.Stack] or of
+// [runtime/debug.Stack], and returns one record per goroutine, in the order of
+// the dump.
+//
+// The panic messages printed before the first goroutine, if any, belong to that
+// goroutine, which gets an [Exception] of type [PanicError].
+func ParseGoroutineDump(reader io.Reader) ([]Goroutine, error) {
+	_, goroutines, err := parseDump(reader)
+	return goroutines, err
+}
+
+// parsePanic reads the crash output of the runtime and returns the panic of the
+// first goroutine, or nil if there is none, with the other goroutines as its
+// snapshot.
+func parsePanic(reader io.Reader) (Exception, error) {
+	messages, goroutines, err := parseDump(reader)
+	switch {
+	case err != nil || messages == nil:
+		return nil, err
+	case len(goroutines) == 0:
+		return buildPanic(messages, nil), nil
+	default:
+		exception := goroutines[0].Exception.(fullException)
+		if len(goroutines) > 1 {
+			exception.Goroutines = goroutines[1:]
+		}
+		return exception, nil
+	}
+}
+
+// waitTime matches the wait time in the state of a goroutine.
+var waitTime = regexp.MustCompile(`^(\d+) minutes$`)
+
+func parseDump(reader io.Reader) ([]panicMessage, []Goroutine, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 1<<20)
+	var messages []panicMessage
+	var goroutines []Goroutine
+	var current *Goroutine
+	for scanner.Scan() {
+		line := scanner.Text()
+		if goroutine, ok := parseGoroutineHeader(line); ok {
+			goroutines = append(goroutines, goroutine)
+			current = &goroutines[len(goroutines)-1]
+			continue
+		}
+		switch {
+		case current == nil && messages == nil:
+			if message, ok := strings.CutPrefix(line, "panic: "); ok {
+				messages = append(messages, parsePanicMessage(message))
+			} else if message, ok := strings.CutPrefix(line, "fatal error: "); ok {
+				messages = append(messages, panicMessage{message: message})
+			}
+		case current == nil:
+			if message, ok := strings.CutPrefix(line, "\tpanic: "); ok && len(goroutines) == 0 {
+				messages = append(messages, parsePanicMessage(message))
+			}
+		case line == "":
+			// goroutines are separated by empty lines
+			current = nil
+		case strings.HasPrefix(line, "created by "):
+			current.CreatedBy, current.CreatorID = parseCreatedBy(line, scanner)
+		default:
+			if frame, ok := parseFrame(line, scanner); ok {
+				current.StackTrace = append(current.StackTrace, frame)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return messages, goroutines, GoroutineDumpError.AddCause(err)
+	}
+	if messages != nil && len(goroutines) > 0 {
+		goroutines[0].Exception = buildPanic(messages, goroutines[0].StackTrace)
+	}
+	return messages, goroutines, nil
+}
+
+// parseGoroutineHeader parses a goroutine header line, in the form of
+// "goroutine 1 [chan receive, 5 minutes]:".
+func parseGoroutineHeader(line string) (Goroutine, bool) {
+	rest, ok := strings.CutPrefix(line, "goroutine ")
+	if !ok || !strings.HasSuffix(rest, "]:") {
+		return Goroutine{}, false
+	}
+	number, rest, _ := strings.Cut(rest, " ")
+	id, err := strconv.Atoi(number)
+	if err != nil {
+		return Goroutine{}, false
+	}
+	index := strings.IndexByte(rest, '[')
+	if index < 0 {
+		return Goroutine{}, false
+	}
+	goroutine := Goroutine{ID: id}
+	for i, item := range strings.Split(rest[index+1:len(rest)-2], ", ") {
+		if i == 0 {
+			goroutine.State = item
+		} else if match := waitTime.FindStringSubmatch(item); match != nil {
+			minutes, _ := strconv.Atoi(match[1])
+			goroutine.Wait = time.Duration(minutes) * time.Minute
+		}
+	}
+	return goroutine, true
+}
+
+// parseCreatedBy parses a creation line, in the form of "created by main.main
+// in goroutine 1", and the location line following it.
+func parseCreatedBy(line string, scanner *bufio.Scanner) (StackFrame, int) {
+	function := strings.TrimPrefix(line, "created by ")
+	creator := 0
+	if before, after, ok := strings.Cut(function, " in goroutine "); ok {
+		function = before
+		creator, _ = strconv.Atoi(after)
+	}
+	frame := StackFrame{Function: function}
+	if scanner.Scan() {
+		frame.File, frame.Line = parseLocation(scanner.Text())
+	}
+	return frame, creator
+}
+
+// parseFrame parses a function line of a goroutine trace and the location line
+// following it.
+func parseFrame(line string, scanner *bufio.Scanner) (StackFrame, bool) {
+	if line == "" || line[0] == '\t' || strings.HasPrefix(line, "...") {
+		return StackFrame{}, false
+	}
+	function := line
+	if index := strings.LastIndexByte(line, '('); index > 0 && strings.HasSuffix(line, ")") {
+		function = line[:index]
+	}
+	frame := StackFrame{Function: function}
+	if scanner.Scan() {
+		frame.File, frame.Line = parseLocation(scanner.Text())
+	}
+	return frame, true
+}
+
+// parseLocation parses a location line of a goroutine trace, in the form of
+// "\tfile:line +0xoffset".
+func parseLocation(line string) (string, int) {
+	location := strings.TrimPrefix(line, "\t")
+	if index := strings.LastIndex(location, " +0x"); index >= 0 {
+		location = location[:index]
+	}
+	index := strings.LastIndexByte(location, ':')
+	if index < 0 {
+		return location, 0
+	}
+	number, err := strconv.Atoi(location[index+1:])
+	if err != nil {
+		return location, 0
+	}
+	return location[:index], number
+}
+
+// panicMessage is a panic message printed by the runtime.
+type panicMessage struct {
+	message string
+	// repanicked is set when the panic was recovered then raised again with the
+	// same value, which adds a "panic" frame to the trace
+	repanicked bool
+}
+
+// parsePanicMessage removes the annotations added by the runtime after a panic
+// message, such as " [recovered]".
+func parsePanicMessage(message string) panicMessage {
+	if strings.HasSuffix(message, "]") {
+		if index := strings.LastIndex(message, " [recovered"); index >= 0 {
+			return panicMessage{
+				message:    message[:index],
+				repanicked: strings.HasSuffix(message, "repanicked]"),
+			}
+		}
+	}
+	return panicMessage{message: message}
+}
+
+// buildPanic builds the exception of a panic from its messages, oldest first,
+// and the trace of the panicking goroutine. Each "panic" frame in the trace
+// starts the trace of the next older panic, or the trace of the same panic
+// before it was raised again.
+func buildPanic(messages []panicMessage, trace StackFrames) Exception {
+	cut := func() StackFrames {
+		segment := trace
+		if index := slices.IndexFunc(trace, isPanicFrame); index >= 0 {
+			segment, trace = trace[:index], trace[index+1:]
+		} else {
+			trace = nil
+		}
+		return segment
+	}
+	var result fullException
+	for i := range messages {
+		message := messages[len(messages)-1-i]
+		if message.repanicked {
+			cut()
+		}
+		current := fullException{
+			Type:      string(PanicError),
+			Recovered: message.message,
+			Stack:     cut(),
+		}
+		if class := classifyMessage(message.message); class != nil {
+			current.Causes = []error{class}
+		}
+		if i == 0 {
+			result = current
+		} else {
+			result = appendOldest(result, current)
+		}
+	}
+	return result
+}
+
+// appendOldest attaches an older panic to the oldest panic of the chain.
+func appendOldest(chain fullException, older fullException) fullException {
+	if len(chain.Suppressed) == 0 {
+		chain.Suppressed = []error{older}
+		return chain
+	}
+	if previous, ok := chain.Suppressed[0].(fullException); ok {
+		chain.Suppressed = []error{appendOldest(previous, older)}
+	}
+	return chain
+}
+
+func isPanicFrame(frame StackFrame) bool {
+	return frame.Function == "panic"
+}
+
+// defaultSnapshotSize is the size of the goroutine dump captured by snapshot
+// when no size is given.
+const defaultSnapshotSize = 1 << 20
+
+// snapshot captures and parses a dump of all goroutines, of at most size bytes.
+// A goroutine truncated by the size limit is dropped.
+func snapshot(size int) []Goroutine {
+	if size <= 0 {
+		size = defaultSnapshotSize
+	}
+	buffer := make([]byte, size)
+	length := runtime.Stack(buffer, true)
+	goroutines, _ := ParseGoroutineDump(bytes.NewReader(buffer[:length]))
+	if length == size && len(goroutines) > 1 {
+		goroutines = goroutines[:len(goroutines)-1]
+	}
+	return goroutines
+}
@@ -0,0 +1,72 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"bytes"
+	"errors"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+const goroutineDump = `panic: runtime error: index out of range [5] with length 3
+
+goroutine 1 [running]:
+main.lookup(...)
+	/app/main.go:12
+main.main()
+	/app/main.go:20 +0x1d
+
+goroutine 7 [chan receive, 5 minutes]:
+main.worker(0xc000012345)
+	/app/worker.go:8 +0x3a
+created by main.main in goroutine 1
+	/app/main.go:17 +0x45
+exit status 2
+`
+
+func TestParseGoroutineDump(t *testing.T) {
+	goroutines, err := exception.ParseGoroutineDump(strings.NewReader(goroutineDump))
+	if err != nil || len(goroutines) != 2 {
+		t.Fatalf("Expected two goroutines but got %#v and %v", goroutines, err)
+	}
+	main, worker := goroutines[0], goroutines[1]
+	if main.ID != 1 || main.State != "running" || len(main.StackTrace) != 2 {
+		t.Errorf("Expected the running main goroutine but got %#v", main)
+	}
+	if main.StackTrace[0] != (exception.StackFrame{Function: "main.lookup", File: "/app/main.go", Line: 12}) {
+		t.Errorf("Expected the lookup frame but got %#v", main.StackTrace[0])
+	}
+	if !errors.Is(main.Exception, exception.PanicError) || !errors.Is(main.Exception, exception.IndexOutOfRangeError) {
+		t.Errorf("Expected an index out of range panic but got %#v", main.Exception)
+	}
+	if worker.ID != 7 || worker.State != "chan receive" || worker.Wait != 5*time.Minute || worker.Exception != nil {
+		t.Errorf("Expected the waiting worker goroutine but got %#v", worker)
+	}
+	if worker.CreatorID != 1 || worker.CreatedBy != (exception.StackFrame{Function: "main.main", File: "/app/main.go", Line: 17}) {
+		t.Errorf("Expected the worker to be created by main but got %#v", worker.CreatedBy)
+	}
+	if worker.StackTrace[0].Function != "main.worker" {
+		t.Errorf("Expected the worker frame but got %#v", worker.StackTrace[0])
+	}
+}
+
+func TestParseGoroutineDumpStack(t *testing.T) {
+	goroutines, err := exception.ParseGoroutineDump(bytes.NewReader(debug.Stack()))
+	if err != nil || len(goroutines) != 1 {
+		t.Fatalf("Expected one goroutine but got %#v and %v", goroutines, err)
+	}
+	trace := goroutines[0].StackTrace
+	if len(trace) < 2 || trace[0].Function != "runtime/debug.Stack" {
+		t.Fatalf("Expected the trace to start from debug.Stack but got %#v", trace)
+	}
+	checkStackTrace(t, trace[1:], "/go-exception_test.TestParseGoroutineDumpStack")
+}
@@ -6,6 +6,13 @@
 
 package exception
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thanhminhmr/go-exception/internal/verbs"
+)
+
 func is(source Exception, target error) bool {
 	if targetException, ok := target.(Exception); ok {
 		return source.GetType() == targetException.GetType()
@@ -23,6 +30,37 @@ func as(source Exception, target any) bool {
 	return false
 }
 
+// wrap returns the error as an [Exception]. A foreign error is wrapped into an
+// [Exception] with the same message and the error as its cause.
+func wrap(err error) Exception {
+	if exception, ok := err.(Exception); ok {
+		return exception
+	}
+	return fullException{
+		Message: err.Error(),
+		Causes:  []error{err},
+	}
+}
+
+// formatMessage formats the message like [fmt.Sprintf], except that %w verbs
+// are formatted like %v, and returns the non-nil errors given to them.
+func formatMessage(message string, parameters []any) (string, []error) {
+	var wrapped []error
+	format := []byte(message)
+	for _, verb := range verbs.Parse(message) {
+		if verb.Character != 'w' {
+			continue
+		}
+		format[verb.End-1] = 'v'
+		if verb.Argument >= 0 && verb.Argument < len(parameters) {
+			if err, ok := parameters[verb.Argument].(error); ok && err != nil {
+				wrapped = append(wrapped, err)
+			}
+		}
+	}
+	return fmt.Sprintf(string(format), parameters...), wrapped
+}
+
 // ========================================
 
 func combine(result *[]error, errors ...error) (changed bool) {
@@ -41,6 +79,10 @@ func combineAdd(result *[]error, changed *bool, err error) {
 		for _, inner := range multiple {
 			combineAdd(result, changed, inner)
 		}
+	} else if aggregated, ok := aggregate(err); ok {
+		for _, inner := range aggregated {
+			combineAdd(result, changed, inner)
+		}
 	} else {
 		*result = append(*result, err)
 		*changed = true
@@ -63,7 +105,47 @@ func concatAdd(result *[]error, err error) {
 		for _, inner := range multiple {
 			concatAdd(result, inner)
 		}
+	} else if aggregated, ok := aggregate(err); ok {
+		for _, inner := range aggregated {
+			concatAdd(result, inner)
+		}
 	} else {
 		*result = append(*result, err)
 	}
 }
+
+// aggregate returns the errors aggregated by a foreign error, such as the
+// result of [errors.Join] or of [fmt.Errorf] with several %w verbs, if
+// flattening is enabled (see [SetFlattenAggregates]) and the error has no
+// message of its own: its message is made of the messages of its errors,
+// separated by spaces and punctuation only.
+func aggregate(err error) ([]error, bool) {
+	if !GetFlattenAggregates() {
+		return nil, false
+	}
+	if _, ok := err.(Exception); ok {
+		return nil, false
+	}
+	unwrapper, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil, false
+	}
+	aggregated := unwrapper.Unwrap()
+	message := err.Error()
+	for _, inner := range aggregated {
+		if inner == nil {
+			continue
+		}
+		index := strings.Index(message, inner.Error())
+		if index < 0 || !isSeparator(message[:index]) {
+			return nil, false
+		}
+		message = message[index+len(inner.Error()):]
+	}
+	return aggregated, isSeparator(message)
+}
+
+// isSeparator reports whether the text only separates messages.
+func isSeparator(text string) bool {
+	return strings.Trim(text, " \t\n,;:|") == ""
+}
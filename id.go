@@ -0,0 +1,35 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// crockford is the Crockford's Base32 alphabet used to encode instance IDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewID returns a new unique instance ID in the ULID format: 26 characters of
+// Crockford's Base32 encoding a 48-bit millisecond timestamp followed by 80
+// random bits. IDs created in different milliseconds sort by creation time.
+func NewID() string {
+	var raw [16]byte
+	binary.BigEndian.PutUint64(raw[:8], uint64(time.Now().UnixMilli())<<16)
+	_, _ = rand.Read(raw[6:])
+	// encode 128 bits as 26 characters of 5 bits, with 2 leading zero bits
+	high := binary.BigEndian.Uint64(raw[:8])
+	low := binary.BigEndian.Uint64(raw[8:])
+	var id [26]byte
+	for i := len(id) - 1; i >= 0; i-- {
+		id[i] = crockford[low&0x1F]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+	return string(id[:])
+}
@@ -0,0 +1,71 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+func TestNewID(t *testing.T) {
+	first := exception.NewID()
+	if len(first) != 26 {
+		t.Fatalf("Expected ID of 26 characters but got \"%s\"", first)
+	}
+	if second := exception.NewID(); first == second {
+		t.Errorf("Expected distinct IDs but got \"%s\" twice", first)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if later := exception.NewID(); later <= first {
+		t.Errorf("Expected \"%s\" to sort after \"%s\"", later, first)
+	}
+}
+
+func TestIDKeptWhenModified(t *testing.T) {
+	const StringError = exception.String("Test")
+	if StringError.GetID() != "" {
+		t.Errorf("Expected String to have no ID but got \"%s\"", StringError.GetID())
+	}
+	err := StringError.FillStackTrace(0)
+	id := err.GetID()
+	if id == "" {
+		t.Fatalf("Expected FillStackTrace to assign an ID")
+	}
+	err = err.SetMessage("Message").AddCause(errors.New("cause")).FillStackTrace(0)
+	if err.GetID() != id {
+		t.Errorf("Expected ID \"%s\" to be kept but got \"%s\"", id, err.GetID())
+	}
+}
+
+func TestRecoverAssignsID(t *testing.T) {
+	defer func() {
+		if recovered := exception.Recover(recover()); recovered == nil || recovered.GetID() == "" {
+			t.Errorf("Expected recovered exception to have an ID")
+		}
+	}()
+	panic("Test")
+}
+
+func TestTimeAssignedWithID(t *testing.T) {
+	before := time.Now()
+	primary := exception.String("Test").FillStackTrace(0)
+	suppressed := exception.String("Suppressed").FillStackTrace(0)
+	primary = primary.AddSuppressed(suppressed)
+	if primary.GetTime().Before(before) || time.Since(primary.GetTime()) < 0 {
+		t.Errorf("Expected creation time after %v but got %v", before, primary.GetTime())
+	}
+	offset := primary.GetSuppressed()[0].(exception.Exception).GetTime().Sub(primary.GetTime())
+	if offset < 0 {
+		t.Errorf("Expected suppressed exception to be created after primary but got offset %v", offset)
+	}
+	if !exception.String("Test").GetTime().IsZero() {
+		t.Errorf("Expected String to have zero creation time")
+	}
+}
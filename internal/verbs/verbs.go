@@ -0,0 +1,92 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+// Package verbs parses the verbs of format strings, as understood by
+// [fmt.Sprintf], for the exception package and its commands.
+package verbs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Verb is a verb of a format string consuming parameters.
+type Verb struct {
+	// Start and End are the offsets of the verb in the format string, from the
+	// percent sign to the verb character included.
+	Start int
+	End   int
+
+	// Character is the verb character, such as 'v' or 'w'.
+	Character byte
+
+	// Argument is the index of the parameter formatted by the verb.
+	Argument int
+
+	// Stars lists the indexes of the parameters consumed by a * width or
+	// precision.
+	Stars []int
+
+	// Indexed reports whether the verb uses an explicit argument index, such as
+	// %[1]d.
+	Indexed bool
+}
+
+// Parse returns the verbs of the format string consuming parameters, that is
+// every verb but the literal percent sign.
+func Parse(format string) []Verb {
+	var verbs []Verb
+	// index of the parameter consumed by the next verb
+	argument := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		verb := Verb{Start: i}
+		for i++; i < len(format); i++ {
+			switch character := format[i]; {
+			case character == '[':
+				if end := strings.IndexByte(format[i:], ']'); end > 0 {
+					if index, err := strconv.Atoi(format[i+1 : i+end]); err == nil {
+						argument = index - 1
+					}
+					verb.Indexed = true
+					i += end
+				}
+				continue
+			case character == '*':
+				verb.Stars = append(verb.Stars, argument)
+				argument++
+				continue
+			case character == '+' || character == '-' || character == '#' || character == ' ' ||
+				character == '0' || character >= '1' && character <= '9' || character == '.':
+				continue
+			case character == '%':
+				// literal percent sign
+			default:
+				verb.End, verb.Character, verb.Argument = i+1, character, argument
+				verbs = append(verbs, verb)
+				argument++
+			}
+			break
+		}
+	}
+	return verbs
+}
+
+// Count returns the number of parameters consumed by the format string. It
+// returns false if the format uses explicit argument indexes, which are not
+// counted.
+func Count(format string) (int, bool) {
+	count := 0
+	for _, verb := range Parse(format) {
+		if verb.Indexed {
+			return 0, false
+		}
+		count += 1 + len(verb.Stars)
+	}
+	return count, true
+}
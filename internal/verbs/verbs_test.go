@@ -0,0 +1,47 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package verbs_test
+
+import (
+	"testing"
+
+	"github.com/thanhminhmr/go-exception/internal/verbs"
+)
+
+func TestParse(t *testing.T) {
+	parsed := verbs.Parse("100%% of %-*d: %[1]w and %.2f")
+	if len(parsed) != 3 {
+		t.Fatalf("Expected 3 verbs but got %#v", parsed)
+	}
+	if verb := parsed[0]; verb.Start != 9 || verb.End != 13 || verb.Character != 'd' || verb.Argument != 1 || len(verb.Stars) != 1 || verb.Stars[0] != 0 {
+		t.Errorf("Expected %%-*d consuming the parameters 0 and 1 but got %#v", verb)
+	}
+	if verb := parsed[1]; verb.Character != 'w' || verb.Argument != 0 || !verb.Indexed {
+		t.Errorf("Expected %%[1]w consuming the parameter 0 but got %#v", verb)
+	}
+	if verb := parsed[2]; verb.Character != 'f' || verb.Argument != 1 || verb.Indexed {
+		t.Errorf("Expected %%.2f consuming the parameter 1 but got %#v", verb)
+	}
+}
+
+func TestCount(t *testing.T) {
+	for format, expected := range map[string]int{
+		"":              0,
+		"100%%":         0,
+		"%s: %d":        2,
+		"%*d and %.*f":  4,
+		"trailing %":    0,
+		"%+v %#x % d %": 3,
+	} {
+		if count, ok := verbs.Count(format); !ok || count != expected {
+			t.Errorf("Expected %q to consume %d parameters but got %d", format, expected, count)
+		}
+	}
+	if _, ok := verbs.Count("%[2]s %[1]s"); ok {
+		t.Errorf("Expected explicit argument indexes not to be counted")
+	}
+}
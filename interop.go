@@ -0,0 +1,90 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"reflect"
+	"runtime"
+)
+
+// StackTraceOf returns the stack trace carried by the error, or nil if there is
+// none. An [Exception] returns its own stack trace. Errors from other libraries
+// are recognized by the method returning their stack trace:
+//
+//   - StackTrace() returning a slice of program counters, such as the
+//     errors.StackTrace of github.com/pkg/errors and github.com/cockroachdb/errors
+//   - StackTrace() returning a slice of [runtime.Frame] or [StackFrame]
+//   - Callers() returning a slice of program counters, as in
+//     github.com/go-errors/errors
+//
+// Only the error itself is inspected, not its causes.
+func StackTraceOf(err error) StackFrames {
+	switch err := err.(type) {
+	case nil:
+		return nil
+	case Exception:
+		return err.GetStackTrace()
+	case interface{ Callers() []uintptr }:
+		return callersFrames(err.Callers())
+	}
+	return reflectedStackTrace(reflect.ValueOf(err))
+}
+
+var (
+	stackFrameType   = reflect.TypeFor[StackFrame]()
+	runtimeFrameType = reflect.TypeFor[runtime.Frame]()
+)
+
+// reflectedStackTrace calls the StackTrace method of the value, if it returns
+// a slice of a known frame type.
+func reflectedStackTrace(value reflect.Value) StackFrames {
+	method := value.MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+	resultType := method.Type().Out(0)
+	if resultType.Kind() != reflect.Slice {
+		return nil
+	}
+	switch elementType := resultType.Elem(); {
+	case elementType.Kind() == reflect.Uintptr:
+		result := method.Call(nil)[0]
+		callers := make([]uintptr, result.Len())
+		for i := range callers {
+			callers[i] = uintptr(result.Index(i).Uint())
+		}
+		return callersFrames(callers)
+	case elementType == stackFrameType:
+		return method.Call(nil)[0].Convert(reflect.TypeFor[StackFrames]()).Interface().(StackFrames)
+	case elementType == runtimeFrameType:
+		frames := method.Call(nil)[0].Interface().([]runtime.Frame)
+		trace := make(StackFrames, len(frames))
+		for i, frame := range frames {
+			trace[i] = StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line, PC: frame.PC}
+		}
+		return trace
+	default:
+		return nil
+	}
+}
+
+// callersFrames returns the stack frames of the program counters returned by
+// [runtime.Callers].
+func callersFrames(callers []uintptr) StackFrames {
+	if len(callers) == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(callers)
+	trace := make(StackFrames, 0, len(callers))
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line, PC: frame.PC})
+		if !more {
+			return trace
+		}
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+// callersError carries its stack trace like github.com/go-errors/errors.
+type callersError []uintptr
+
+func (e callersError) Error() string {
+	return "callers"
+}
+
+func (e callersError) Callers() []uintptr {
+	return e
+}
+
+// framesError carries its stack trace as runtime frames.
+type framesError []runtime.Frame
+
+func (e framesError) Error() string {
+	return "frames"
+}
+
+func (e framesError) StackTrace() []runtime.Frame {
+	return e
+}
+
+func newCallersError() callersError {
+	callers := make([]uintptr, 32)
+	return callers[:runtime.Callers(1, callers)]
+}
+
+func TestStackTraceOf(t *testing.T) {
+	const function = "github.com/thanhminhmr/go-exception_test.TestStackTraceOf"
+	trace := exception.StackTraceOf(pkgerrors.New("Test"))
+	if len(trace) == 0 || trace[0].Function != function {
+		t.Errorf("Expected the pkg/errors stack trace but got %v", trace)
+	}
+	trace = exception.StackTraceOf(newCallersError())
+	if len(trace) < 2 || trace[1].Function != function {
+		t.Errorf("Expected the callers stack trace but got %v", trace)
+	}
+	trace = exception.StackTraceOf(framesError{{Function: "main.main", File: "main.go", Line: 3}})
+	if len(trace) != 1 || trace[0].Function != "main.main" || trace[0].Line != 3 {
+		t.Errorf("Expected the runtime frames but got %v", trace)
+	}
+	if trace := exception.StackTraceOf(io.EOF); trace != nil {
+		t.Errorf("Expected no stack trace but got %v", trace)
+	}
+}
+
+func TestRenderForeignStackTrace(t *testing.T) {
+	err := CloseError.AddCause(pkgerrors.New("Test"))
+	var text bytes.Buffer
+	if err := exception.WriteText(&text, err); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "\tcaused by: Test\n\t\tat github.com/thanhminhmr/go-exception_test.TestRenderForeignStackTrace (") {
+		t.Errorf("Expected the stack trace of the cause but got:\n%s", text.String())
+	}
+	var output bytes.Buffer
+	if err := exception.WriteJSON(&output, err); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Cause struct {
+			Message    string           `json:"message"`
+			StackTrace []map[string]any `json:"stack_trace"`
+		} `json:"cause"`
+	}
+	if err := json.Unmarshal(output.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Cause.Message != "Test" || len(decoded.Cause.StackTrace) == 0 {
+		t.Errorf("Expected the stack trace of the cause but got %s", output.String())
+	}
+}
@@ -6,7 +6,12 @@
 
 package exception
 
-import "fmt"
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
 
 // Join combines multiple errors into a single [Exception] with an empty type.
 //
@@ -16,9 +21,14 @@ import "fmt"
 // been modified further (other than adding more causes), their causes are
 // automatically unwrapped and merged into the new [Exception].
 //
+// Likewise, errors aggregating other errors without a message of their own,
+// such as the results of [errors.Join] or of [fmt.Errorf] with several %w
+// verbs, are flattened unless disabled with [SetFlattenAggregates].
+//
 // The resulting [Exception] exposes all non-nil errors, including those from
 // unboxed joins, as its causes. Other details such as the message, suppressed
-// errors, recovered value, and stack trace are left empty.
+// errors, recovered value, and stack trace are left empty. Its Error method
+// renders the messages of the causes with the active [JoinStyle].
 func Join(errors ...error) Exception {
 	var multiple []error
 	if !combine(&multiple, errors...) {
@@ -27,13 +37,93 @@ func Join(errors ...error) Exception {
 	return multipleErrors(multiple)
 }
 
+// JoinStyle renders the messages of joined errors, for the Error method of the
+// results of [Join] and of the exceptions built from them without a type or a
+// message of their own.
+type JoinStyle func(errors []error) string
+
+// JoinLines renders the messages of the errors on separate lines, like
+// [errors.Join]. This is the default [JoinStyle].
+func JoinLines(errors []error) string {
+	var builder strings.Builder
+	for i, err := range errors {
+		if i > 0 {
+			builder.WriteByte('\n')
+		}
+		builder.WriteString(err.Error())
+	}
+	return builder.String()
+}
+
+// JoinCounted renders the messages of the errors on a single line, prefixed by
+// their count, such as "3 errors: a; b; c".
+func JoinCounted(errors []error) string {
+	var builder strings.Builder
+	builder.WriteString(strconv.Itoa(len(errors)))
+	if len(errors) == 1 {
+		builder.WriteString(" error: ")
+	} else {
+		builder.WriteString(" errors: ")
+	}
+	for i, err := range errors {
+		if i > 0 {
+			builder.WriteString("; ")
+		}
+		builder.WriteString(err.Error())
+	}
+	return builder.String()
+}
+
+var joinStyle atomic.Pointer[JoinStyle]
+
+// SetJoinStyle replaces the active [JoinStyle] and returns the previous one. A
+// nil style restores [JoinLines].
+func SetJoinStyle(style JoinStyle) (previous JoinStyle) {
+	var stored *JoinStyle
+	if style != nil {
+		stored = &style
+	}
+	if previousStored := joinStyle.Swap(stored); previousStored != nil {
+		return *previousStored
+	}
+	return JoinLines
+}
+
+// GetJoinStyle returns the active [JoinStyle].
+func GetJoinStyle() JoinStyle {
+	if style := joinStyle.Load(); style != nil {
+		return *style
+	}
+	return JoinLines
+}
+
+// keepAggregates disables the flattening of foreign aggregates, which is
+// enabled by default.
+var keepAggregates atomic.Bool
+
+// SetFlattenAggregates enables or disables the flattening of foreign errors
+// aggregating other errors, such as the results of [errors.Join], when they
+// are given to [Join], AddCause or AddSuppressed, and returns the previous
+// setting. Flattening is enabled by default, so that [Exception.GetCause]
+// returns the same errors whatever produced the aggregate.
+func SetFlattenAggregates(flatten bool) (previous bool) {
+	return !keepAggregates.Swap(!flatten)
+}
+
+// GetFlattenAggregates reports whether foreign aggregates are flattened.
+func GetFlattenAggregates() bool {
+	return !keepAggregates.Load()
+}
+
 // type check
 var _ Exception = multipleErrors{}
 
 type multipleErrors []error
 
+// Error returns the messages of the joined errors, rendered with the active
+// [JoinStyle].
 func (e multipleErrors) Error() string {
-	return ""
+	return GetJoinStyle()(e)
 }
 
 func (e multipleErrors) GetType() string {
@@ -51,12 +141,16 @@ func (e multipleErrors) SetMessage(message string, parameters ...any) Exception
 	case len(parameters) == 0:
 		return fullException{
 			Message: message,
-			Cause:   e,
+			Causes:  e,
 		}
 	default:
+		formatted, wrapped := formatMessage(message, parameters)
+		var causes []error
+		concat(&causes, e...)
+		concat(&causes, wrapped...)
 		return fullException{
-			Message: fmt.Sprintf(message, parameters...),
-			Cause:   e,
+			Message: formatted,
+			Causes:  causes,
 		}
 	}
 }
@@ -78,7 +172,7 @@ func (e multipleErrors) AddSuppressed(errors ...error) Exception {
 	var suppressed []error
 	if combine(&suppressed, errors...) {
 		return fullException{
-			Cause:      e,
+			Causes:     e,
 			Suppressed: suppressed,
 		}
 	}
@@ -94,7 +188,7 @@ func (e multipleErrors) SetRecovered(recovered any) Exception {
 		return e
 	}
 	return fullException{
-		Cause:     e,
+		Causes:    e,
 		Recovered: recovered,
 	}
 }
@@ -104,12 +198,30 @@ func (e multipleErrors) GetStackTrace() StackFrames {
 }
 
 func (e multipleErrors) FillStackTrace(skip int) Exception {
+	return e.withStackTrace(StackTrace(skip + 1))
+}
+
+func (e multipleErrors) withStackTrace(trace StackFrames) Exception {
 	return fullException{
-		Cause:      e,
-		StackTrace: StackTrace(skip + 1),
+		Causes: e,
+		Stack:  trace,
+		ID:     NewID(),
+		Time:   time.Now(),
 	}
 }
 
+func (e multipleErrors) GetID() string {
+	return ""
+}
+
+func (e multipleErrors) GetTime() time.Time {
+	return time.Time{}
+}
+
+func (e multipleErrors) GetGoroutines() []Goroutine {
+	return nil
+}
+
 func (e multipleErrors) __() {}
 
 func (e multipleErrors) Unwrap() []error {
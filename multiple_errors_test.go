@@ -0,0 +1,84 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+func TestJoinFlattensAggregates(t *testing.T) {
+	expected := []error{io.EOF, os.ErrClosed, ParseError}
+	joined := exception.Join(errors.Join(io.EOF, os.ErrClosed), ParseError)
+	if causes := joined.GetCause(); !slices.Equal(causes, expected) {
+		t.Errorf("Expected %v but got %v", expected, causes)
+	}
+	wrapped := CloseError.AddCause(fmt.Errorf("%w: %w", io.EOF, os.ErrClosed), ParseError)
+	if causes := wrapped.GetCause(); !slices.Equal(causes, expected) {
+		t.Errorf("Expected %v but got %v", expected, causes)
+	}
+	suppressed := CloseError.AddSuppressed(errors.Join(io.EOF, errors.Join(os.ErrClosed, ParseError)))
+	if errs := suppressed.GetSuppressed(); !slices.Equal(errs, expected) {
+		t.Errorf("Expected %v but got %v", expected, errs)
+	}
+}
+
+func TestJoinKeepsAggregatesWithMessage(t *testing.T) {
+	aggregate := fmt.Errorf("read %w then %w", io.EOF, os.ErrClosed)
+	if causes := exception.Join(aggregate).GetCause(); len(causes) != 1 || causes[0] != aggregate {
+		t.Errorf("Expected the aggregate to be kept but got %v", causes)
+	}
+	// an exception is never flattened, even though it unwraps to its causes
+	failure := CloseError.AddCause(io.EOF, os.ErrClosed)
+	if causes := exception.Join(failure).GetCause(); len(causes) != 1 || !errors.Is(causes[0], CloseError) {
+		t.Errorf("Expected the exception to be kept but got %v", causes)
+	}
+}
+
+func TestSetFlattenAggregates(t *testing.T) {
+	previous := exception.SetFlattenAggregates(false)
+	defer exception.SetFlattenAggregates(previous)
+	if !previous {
+		t.Errorf("Expected flattening to be enabled by default")
+	}
+	aggregate := errors.Join(io.EOF, os.ErrClosed)
+	if causes := exception.Join(aggregate).GetCause(); len(causes) != 1 || causes[0] != aggregate {
+		t.Errorf("Expected the aggregate to be kept but got %v", causes)
+	}
+}
+
+func TestJoinError(t *testing.T) {
+	joined := exception.Join(io.EOF, ParseError)
+	if expected := "EOF\n" + ParseError.Error(); joined.Error() != expected {
+		t.Errorf("Expected %q but got %q", expected, joined.Error())
+	}
+	// a type-less wrapper falls back to the rendering of its causes
+	if wrapped := joined.FillStackTrace(0); wrapped.Error() != joined.Error() {
+		t.Errorf("Expected %q but got %q", joined.Error(), wrapped.Error())
+	}
+}
+
+func TestSetJoinStyle(t *testing.T) {
+	previous := exception.SetJoinStyle(exception.JoinCounted)
+	defer exception.SetJoinStyle(previous)
+	joined := exception.Join(io.EOF, os.ErrClosed, ParseError)
+	if expected := "3 errors: EOF; file already closed; " + ParseError.Error(); joined.Error() != expected {
+		t.Errorf("Expected %q but got %q", expected, joined.Error())
+	}
+	if style := exception.SetJoinStyle(nil); style == nil {
+		t.Errorf("Expected the previous style to be returned")
+	}
+	if expected := "EOF\nfile already closed\n" + ParseError.Error(); joined.Error() != expected {
+		t.Errorf("Expected %q but got %q", expected, joined.Error())
+	}
+}
@@ -6,6 +6,8 @@
 
 package exception
 
+import "time"
+
 // PanicError is the default type for exceptions created by [Panic] and
 // [Recover].
 const PanicError = String("panicked")
@@ -37,9 +39,12 @@ const PanicError = String("panicked")
 func Panic(recovered any) {
 	if err, ok := recovered.(Exception); !ok || err.GetType() != string(PanicError) {
 		recovered = fullException{
-			Type:       string(PanicError),
-			Recovered:  recovered,
-			StackTrace: StackTrace(1),
+			Type:      string(PanicError),
+			Causes:    panicCause(recovered),
+			Recovered: recovered,
+			Stack:     StackTrace(1),
+			ID:        NewID(),
+			Time:      time.Now(),
 		}
 	}
 	panic(recovered)
@@ -56,7 +61,12 @@ func Panic(recovered any) {
 //
 // Otherwise, [Recover] creates a new [Exception] that uses [PanicError] as its
 // type, keeps the recovered value, and records the stack trace starting from the
-// location where the panic occurred.
+// location where the panic occurred. If the recovered value is a recognized
+// [runtime.Error], such as a nil pointer dereference, its class is attached as
+// the first cause (see [NilPointerError]).
+//
+// The active [RecoveryPolicy] is honored: values it never recovers are
+// re-panicked untouched, and fatal runtime panics terminate the process.
 //
 // Typical usage in a deferred function:
 //
@@ -66,23 +76,31 @@ func Panic(recovered any) {
 //	    }
 //	}()
 func Recover(recovered any) Exception {
-	if recovered == nil {
-		return nil
-	}
-	if err, ok := recovered.(Exception); ok && err.GetType() == string(PanicError) {
-		return err
-	}
-	// skip to panic frame if exists
-	trace := StackTrace(1)
-	for i, frame := range trace {
-		if frame.Function == "runtime.gopanic" {
-			trace = trace[i+1:]
-			break
-		}
-	}
-	return fullException{
-		Type:       string(PanicError),
-		Recovered:  recovered,
-		StackTrace: trace,
-	}
+	return GetRecoveryPolicy().recover(recovered, 1)
+}
+
+// Cleanup calls the function as a deferred cleanup without losing the panic
+// that may be unwinding at the same time. It must be deferred directly, and is
+// usually combined with a recover handler deferred at the top of the function:
+//
+//	defer func() {
+//	    if err := exception.Recover(recover()); err != nil {
+//	        // handle exception
+//	    }
+//	}()
+//	defer exception.Cleanup(func() {
+//	    // cleanup that may panic
+//	})
+//
+// If a panic is unwinding, [Cleanup] captures it with [Recover] before calling
+// the function. If the function panics too, the new panic is also captured with
+// [Recover], and the earlier one is attached to it with
+// [Exception.AddSuppressed]. Either way, the resulting [Exception] is
+// re-panicked, so the handler sees the whole chain of panics, each one with the
+// stack trace of its own panic site.
+//
+// The active [RecoveryPolicy] is honored: a value it never recovers is
+// re-panicked untouched once the function returns.
+func Cleanup(function func()) {
+	GetRecoveryPolicy().cleanup(recover(), function)
 }
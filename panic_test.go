@@ -44,3 +44,42 @@ func TestRecoverRawPanic(t *testing.T) {
 	}()
 	panic("Test")
 }
+
+func panicInCleanup() {
+	defer exception.Cleanup(func() {
+		panic("Cleanup")
+	})
+	panic("Original")
+}
+
+func TestCleanupNestedPanic(t *testing.T) {
+	defer func() {
+		recovered := exception.Recover(recover())
+		if recovered == nil || recovered.GetRecovered() != "Cleanup" {
+			t.Fatalf("Expected the cleanup panic but got %#v", recovered)
+		}
+		checkStackTrace(t, recovered.GetStackTrace(), "/go-exception_test.panicInCleanup.func1")
+		suppressed := recovered.GetSuppressed()
+		if len(suppressed) != 1 {
+			t.Fatalf("Expected the original panic to be suppressed but got %#v", suppressed)
+		}
+		original := suppressed[0].(exception.Exception)
+		if original.GetRecovered() != "Original" {
+			t.Errorf("Expected the original panic but got %#v", original)
+		}
+		checkStackTrace(t, original.GetStackTrace(), "/go-exception_test.panicInCleanup")
+	}()
+	panicInCleanup()
+}
+
+func TestCleanupWithoutPanic(t *testing.T) {
+	called := false
+	func() {
+		defer exception.Cleanup(func() {
+			called = true
+		})
+	}()
+	if !called {
+		t.Errorf("Expected cleanup function to be called")
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !no_pkgerrors
+
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	pkgerrors "github.com/pkg/errors"
+)
+
+// pkgErrors returns the stack trace in the format of github.com/pkg/errors, or
+// nil if the program counters of the frames are unknown.
+func (s StackFrames) pkgErrors() pkgerrors.StackTrace {
+	var trace pkgerrors.StackTrace
+	for _, frame := range s {
+		if frame.PC != 0 {
+			// a pkg/errors frame holds the program counter + 1
+			trace = append(trace, pkgerrors.Frame(frame.PC+1))
+		}
+	}
+	return trace
+}
+
+// StackTrace returns the stack trace of this exception in the format of
+// github.com/pkg/errors, for the tools that only understand that format, such
+// as error reporting SDKs. Exceptions do not implement the Cause method of
+// pkg/errors, since one without causes would have none to return: tools walk
+// their causes through Unwrap instead, and pkg/errors.Cause returns the
+// exception itself. Build with the no_pkgerrors tag to drop these
+// adapters and the dependency on github.com/pkg/errors.
+func (e fullException) StackTrace() pkgerrors.StackTrace {
+	return e.Stack.pkgErrors()
+}
+
+// StackTrace returns the stack trace of this exception in the format of
+// github.com/pkg/errors.
+func (b *Base) StackTrace() pkgerrors.StackTrace {
+	return b.exception.Stack.pkgErrors()
+}
@@ -0,0 +1,45 @@
+//go:build !no_pkgerrors
+
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestPkgErrorsConventions(t *testing.T) {
+	err := ParseError.FillStackTrace(0)
+	if cause := pkgerrors.Cause(err); !reflect.DeepEqual(cause, err) {
+		t.Errorf("Expected the exception itself as the cause but got %#v", cause)
+	}
+	if cause := pkgerrors.Cause(pkgerrors.Wrap(err, "Test")); !reflect.DeepEqual(cause, err) {
+		t.Errorf("Expected the wrapped exception as the cause but got %#v", cause)
+	}
+	if cause := pkgerrors.Cause(ParseError); cause != ParseError {
+		t.Errorf("Expected the declared constant as the cause but got %#v", cause)
+	}
+	if _, ok := err.(interface{ Cause() error }); ok {
+		t.Errorf("Expected no Cause method on %#v", err)
+	}
+	tracer, ok := err.(interface{ StackTrace() pkgerrors.StackTrace })
+	if !ok {
+		t.Fatalf("Expected the pkg/errors stack trace of %#v", err)
+	}
+	if formatted := fmt.Sprintf("%+v", tracer.StackTrace()); !strings.Contains(formatted, "TestPkgErrorsConventions") {
+		t.Errorf("Expected the test function in the stack trace but got %s", formatted)
+	}
+	failure := NewOrderFailure("42").FillStackTrace(0)
+	if _, ok := failure.(interface{ StackTrace() pkgerrors.StackTrace }); !ok {
+		t.Errorf("Expected the pkg/errors stack trace of %#v", failure)
+	}
+}
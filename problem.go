@@ -0,0 +1,70 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// WriteProblem writes the error as an HTTP problem details response, as
+// defined by RFC 9457, so that a user can report the instance ID of the
+// exception and support can find the matching log entry.
+//
+// The status is the HTTP status of the registered [Definition] of the error
+// (see [Lookup]), or 500 if there is none. The title is the type of the
+// exception and the detail its message, with the active [RedactionPolicy]
+// applied. The instance ID of the error, or of its first cause carrying one,
+// is written as the "id" member. An error from another library only gets the
+// text of its status, since its message was not written for users.
+func WriteProblem(writer http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if definition, ok := Lookup(err); ok && definition.HTTPStatus != 0 {
+		status = definition.HTTPStatus
+	}
+	problem := map[string]any{"status": status}
+	if exception, ok := err.(Exception); ok && exception.GetType() != "" {
+		policy := GetRedactionPolicy()
+		problem["title"] = exception.GetType()
+		if message := exception.GetMessage(); message != "" {
+			problem["detail"] = jsonMasked(policy, "message", policy.RedactMessage(message))
+		}
+	} else {
+		problem["title"] = http.StatusText(status)
+	}
+	if id := idOf(err); id != "" {
+		problem["id"] = id
+	}
+	writer.Header().Set("Content-Type", "application/problem+json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(problem)
+}
+
+// idOf returns the instance ID of the error, or of its first cause carrying
+// one, depth first.
+func idOf(err error) string {
+	switch unwrapped := err.(type) {
+	case Exception:
+		if id := unwrapped.GetID(); id != "" {
+			return id
+		}
+		for _, cause := range unwrapped.GetCause() {
+			if id := idOf(cause); id != "" {
+				return id
+			}
+		}
+	case interface{ Unwrap() error }:
+		return idOf(unwrapped.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, cause := range unwrapped.Unwrap() {
+			if id := idOf(cause); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+func TestWriteProblem(t *testing.T) {
+	err := AccountLockedError.FillStackTrace(0)
+	recorder := httptest.NewRecorder()
+	exception.WriteProblem(recorder, fmt.Errorf("login: %w", err))
+	if recorder.Code != 423 || recorder.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("Expected a 423 problem response but got %d %s", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	var problem map[string]any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem["title"] != "Locked" || problem["id"] != err.GetID() {
+		t.Errorf("Expected the status text and the ID of the cause but got %v", problem)
+	}
+
+	recorder = httptest.NewRecorder()
+	exception.WriteProblem(recorder, err)
+	problem = nil
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem["title"] != "AccountLocked" || problem["detail"] != "too many attempts" || problem["id"] != err.GetID() {
+		t.Errorf("Expected the type, the message and the ID but got %v", problem)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// SignalError is the type of the cancellation cause recorded by [Main] when
+// the program receives a signal. The signal itself is the payload of the
+// cause, see [PayloadOf].
+const SignalError = String("signal received")
+
+// Exit codes following the conventions of sysexits.h, for use in
+// [Program.ExitCodes].
+const (
+	ExitUsage       = 64 // command line usage error
+	ExitDataError   = 65 // data format error
+	ExitNoInput     = 66 // cannot open input
+	ExitUnavailable = 69 // service unavailable
+	ExitSoftware    = 70 // internal software error
+	ExitIOError     = 74 // input/output error
+	ExitTemporary   = 75 // temporary failure, the user is invited to retry
+	ExitConfig      = 78 // configuration error
+)
+
+// FormatEnvironment is the environment variable selecting how [Main] renders
+// the error returned by the program: "json" for [WriteJSON], anything else for
+// [WriteText].
+const FormatEnvironment = "EXCEPTION_FORMAT"
+
+// Program describes how [Program.Run] runs the real main function of a command
+// line program. The zero value is ready to use.
+type Program struct {
+	// ExitCodes maps exception types to process exit codes. The type of the
+	// returned error is looked up first, then the types of its causes. Unless
+	// overridden, [PanicError] maps to [ExitSoftware]. Any other error exits
+	// with status 1.
+	ExitCodes map[string]int
+
+	// Signals lists the signals that cancel the context. When empty, SIGINT and
+	// SIGTERM are used.
+	Signals []os.Signal
+
+	// Output receives the rendered error. When nil, the standard error is used.
+	Output io.Writer
+}
+
+// Main runs the real main function of a command line program with a zero
+// [Program] and exits the process with the resulting exit code:
+//
+//	func main() {
+//	    exception.Main(run)
+//	}
+//
+//	func run(ctx context.Context) error {
+//	    ...
+//	}
+func Main(main func(ctx context.Context) error) {
+	os.Exit(new(Program).Run(main))
+}
+
+// Run calls the main function and returns the exit code of the process.
+//
+// The context passed to the main function is cancelled when one of the
+// [Program.Signals] is received, with an [Exception] of type [SignalError] as
+// its cause (see [context.Cause]). The signals are then no longer caught, so
+// that a second one terminates the process as usual if the shutdown hangs. A
+// panic escaping the main function is converted with [Recover]. A resulting
+// error is rendered to [Program.Output] in the format selected by
+// [FormatEnvironment], and mapped to an exit code: if a signal was received,
+// the code is 128 plus the signal number, as done by shells, otherwise it
+// comes from [Program.ExitCodes].
+func (p *Program) Run(main func(ctx context.Context) error) int {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	signals := make(chan os.Signal, 1)
+	if len(p.Signals) == 0 {
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	} else {
+		signal.Notify(signals, p.Signals...)
+	}
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case received := <-signals:
+			// a second signal terminates the process if the shutdown hangs
+			signal.Stop(signals)
+			cancel(TypedFrom(SignalError, received).SetMessage("%s", received))
+		case <-ctx.Done():
+		}
+	}()
+	err := p.call(ctx, main)
+	if err == nil {
+		return 0
+	}
+	p.render(err)
+	if received, ok := PayloadOf[os.Signal](context.Cause(ctx)); ok {
+		if number, ok := received.(syscall.Signal); ok {
+			return 128 + int(number)
+		}
+	}
+	return p.exitCode(err)
+}
+
+func (p *Program) call(ctx context.Context, main func(ctx context.Context) error) (err error) {
+	defer HandleRecover(&err)
+	return main(ctx)
+}
+
+func (p *Program) render(err error) {
+	output := p.Output
+	if output == nil {
+		output = os.Stderr
+	}
+	if os.Getenv(FormatEnvironment) == "json" {
+		_ = WriteJSON(output, err)
+	} else {
+		_ = WriteText(output, err)
+	}
+}
+
+func (p *Program) exitCode(err error) int {
+	if exception, ok := err.(Exception); ok {
+		if code, ok := p.ExitCodes[exception.GetType()]; ok {
+			return code
+		}
+		if exception.GetType() == string(PanicError) {
+			return ExitSoftware
+		}
+	}
+	switch unwrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := unwrapped.Unwrap(); cause != nil {
+			return p.exitCode(cause)
+		}
+	case interface{ Unwrap() []error }:
+		for _, cause := range unwrapped.Unwrap() {
+			if code := p.exitCode(cause); code != 1 {
+				return code
+			}
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitTemporary
+	}
+	return 1
+}
@@ -0,0 +1,134 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+const UsageError = exception.String("UsageError: missing argument")
+
+func TestProgramExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		main     func(ctx context.Context) error
+		expected int
+	}{
+		{"Success", func(ctx context.Context) error { return nil }, 0},
+		{"Error", func(ctx context.Context) error { return fmt.Errorf("failed") }, 1},
+		{"Mapped", func(ctx context.Context) error { return UsageError }, exception.ExitUsage},
+		{"MappedCause", func(ctx context.Context) error { return fmt.Errorf("wrapped: %w", UsageError) }, exception.ExitUsage},
+		{"Panic", func(ctx context.Context) error { panic("Test") }, exception.ExitSoftware},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var output bytes.Buffer
+			program := exception.Program{
+				ExitCodes: map[string]int{UsageError.GetType(): exception.ExitUsage},
+				Output:    &output,
+			}
+			if code := program.Run(test.main); code != test.expected {
+				t.Errorf("Expected exit code %d but got %d", test.expected, code)
+			}
+			if test.expected != 0 && output.Len() == 0 {
+				t.Errorf("Expected the error to be rendered")
+			}
+		})
+	}
+}
+
+func TestProgramRenderJSON(t *testing.T) {
+	t.Setenv(exception.FormatEnvironment, "json")
+	// the recovered value is compared unredacted
+	previous := exception.SetRedactionPolicy(nil)
+	defer exception.SetRedactionPolicy(previous)
+	var output bytes.Buffer
+	program := exception.Program{Output: &output}
+	program.Run(func(ctx context.Context) error {
+		panic("Test")
+	})
+	var object map[string]any
+	if err := json.Unmarshal(output.Bytes(), &object); err != nil {
+		t.Fatalf("Expected JSON output but got %s", output.String())
+	}
+	if object["error"] != "panicked" || object["recovered"] != "Test" {
+		t.Errorf("Expected the recovered panic but got %s", output.String())
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var output bytes.Buffer
+	err := UsageError.AddCause(fmt.Errorf("cause")).FillStackTrace(0)
+	if writeErr := exception.WriteText(&output, err); writeErr != nil {
+		t.Fatalf("Expected no error but got %v", writeErr)
+	}
+	text := output.String()
+	for _, expected := range []string{
+		"UsageError: missing argument\n",
+		"\tid: " + err.GetID(),
+		"\tat github.com/thanhminhmr/go-exception_test.TestWriteText (",
+		"\tcaused by: cause\n",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected output to contain %q but got %s", expected, text)
+		}
+	}
+}
+
+func TestRenderersRedact(t *testing.T) {
+	previous := exception.SetRedactionPolicy(&exception.RedactionPolicy{
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`token=\S+`)},
+	})
+	defer exception.SetRedactionPolicy(previous)
+
+	err := exception.String("Test: login with token=abc").
+		AddCause(fmt.Errorf("bad token=xyz")).
+		SetRecovered("password")
+	for name, write := range map[string]func(*bytes.Buffer) error{
+		"text": func(output *bytes.Buffer) error { return exception.WriteText(output, err) },
+		"JSON": func(output *bytes.Buffer) error { return exception.WriteJSON(output, err) },
+	} {
+		var output bytes.Buffer
+		if writeErr := write(&output); writeErr != nil {
+			t.Fatal(writeErr)
+		}
+		for _, leaked := range []string{"abc", "xyz", "password"} {
+			if strings.Contains(output.String(), leaked) {
+				t.Errorf("Expected %q to be redacted from the %s output but got %s", leaked, name, output.String())
+			}
+		}
+	}
+}
+
+func TestWriteTextSuppressedOffset(t *testing.T) {
+	err := UsageError.FillStackTrace(0)
+	time.Sleep(time.Millisecond)
+	suppressed := ParseError.FillStackTrace(0)
+	var output bytes.Buffer
+	if writeErr := exception.WriteText(&output, err.AddSuppressed(suppressed)); writeErr != nil {
+		t.Fatalf("Expected no error but got %v", writeErr)
+	}
+	offset := regexp.MustCompile(`\tid: ` + suppressed.GetID() + ` \(\S+, \+(\S+)\)\n`).FindStringSubmatch(output.String())
+	if offset == nil {
+		t.Fatalf("Expected the offset of the suppressed exception but got %s", output.String())
+	}
+	if duration, parseErr := time.ParseDuration(offset[1]); parseErr != nil || duration < time.Millisecond {
+		t.Errorf("Expected an offset of at least 1ms but got %s", offset[1])
+	}
+	if strings.Count(output.String(), ", +") != 1 {
+		t.Errorf("Expected only the suppressed exception to have an offset but got %s", output.String())
+	}
+}
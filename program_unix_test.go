@@ -0,0 +1,48 @@
+//go:build unix
+
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+func TestProgramSecondSignal(t *testing.T) {
+	if os.Getenv("PROGRAM_SECOND_SIGNAL") != "" {
+		new(exception.Program).Run(func(ctx context.Context) error {
+			_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+			<-ctx.Done()
+			// the shutdown hangs until the second signal
+			_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+			time.Sleep(time.Minute)
+			return nil
+		})
+		return
+	}
+	command := exec.Command(os.Args[0], "-test.run=^TestProgramSecondSignal$")
+	command.Env = append(os.Environ(), "PROGRAM_SECOND_SIGNAL=1")
+	timer := time.AfterFunc(10*time.Second, func() {
+		_ = command.Process.Kill()
+	})
+	defer timer.Stop()
+	var exitErr *exec.ExitError
+	if err := command.Run(); !errors.As(err, &exitErr) {
+		t.Fatalf("Expected the process to be terminated but got %v", err)
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); !ok || !status.Signaled() || status.Signal() != syscall.SIGTERM {
+		t.Errorf("Expected the process to be terminated by the second signal but got %v", exitErr)
+	}
+}
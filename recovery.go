@@ -0,0 +1,221 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// RecoveryPolicy controls which panics may be recovered by the recovery helpers
+// of this package, such as [Recover], [HandleRecover] and [Cleanup]. A nil
+// policy recovers every panic.
+//
+// The active policy is set with [SetRecoveryPolicy]. A policy can also be used
+// for a single handler through its own methods:
+//
+//	var policy = &exception.RecoveryPolicy{
+//	    Values: []any{http.ErrAbortHandler},
+//	}
+//
+//	defer policy.HandleRecover(&err)
+//
+// Note that [runtime.Goexit] does not panic, so it is never seen by a recovery
+// helper and always terminates the goroutine.
+type RecoveryPolicy struct {
+	// Values lists panic values that are never recovered, but re-panicked
+	// untouched. Errors are matched with [errors.Is], other values with ==.
+	Values []any
+
+	// Types lists types of panic values that are never recovered, but
+	// re-panicked untouched. An interface type matches every value implementing
+	// it.
+	Types []reflect.Type
+
+	// Fatal lists classes of runtime panics, such as [NilPointerError], that
+	// terminate the process once recovered.
+	Fatal []String
+
+	// Exit is called with the [Exception] of a fatal runtime panic. It is expected
+	// not to return. When nil, the exception is written to the standard error and
+	// the process exits with status 2, like an unrecovered panic.
+	Exit func(err Exception)
+
+	// Snapshot enables capturing all goroutines when a panic is recovered, so
+	// that the state of the other goroutines, such as the ones holding a lock,
+	// can be inspected with [Exception.GetGoroutines].
+	Snapshot bool
+
+	// SnapshotSize caps the size in bytes of the goroutine dump captured for a
+	// snapshot. Goroutines beyond it are dropped. When zero, 1 MiB is used.
+	SnapshotSize int
+}
+
+// Recover behaves like the package-level [Recover], using this policy instead
+// of the active one.
+func (p *RecoveryPolicy) Recover(recovered any) Exception {
+	return p.recover(recovered, 1)
+}
+
+// HandleRecover behaves like the package-level [HandleRecover], using this
+// policy instead of the active one. It must be deferred directly.
+func (p *RecoveryPolicy) HandleRecover(err *error) {
+	p.handleRecover(err, recover())
+}
+
+// Cleanup behaves like the package-level [Cleanup], using this policy instead
+// of the active one. It must be deferred directly.
+func (p *RecoveryPolicy) Cleanup(function func()) {
+	p.cleanup(recover(), function)
+}
+
+// IsRecoverable reports whether the panic value may be recovered, that is, it
+// is neither listed in [RecoveryPolicy.Values] nor of a type listed in
+// [RecoveryPolicy.Types]. For an [Exception] created by [Panic], the value it
+// wraps is checked as well.
+func (p *RecoveryPolicy) IsRecoverable(recovered any) bool {
+	if p == nil || recovered == nil {
+		return true
+	}
+	if err, ok := recovered.(Exception); ok && err.GetType() == string(PanicError) {
+		if wrapped := err.GetRecovered(); wrapped != nil && !p.IsRecoverable(wrapped) {
+			return false
+		}
+	}
+	recoveredError, isError := recovered.(error)
+	recoveredType := reflect.TypeOf(recovered)
+	for _, value := range p.Values {
+		if valueError, ok := value.(error); ok && isError {
+			if errors.Is(recoveredError, valueError) {
+				return false
+			}
+		} else if recoveredType == reflect.TypeOf(value) && recoveredType.Comparable() && recovered == value {
+			return false
+		}
+	}
+	for _, neverType := range p.Types {
+		if recoveredType == neverType || neverType.Kind() == reflect.Interface && recoveredType.Implements(neverType) {
+			return false
+		}
+	}
+	return true
+}
+
+// isFatal reports whether the exception holds a class of runtime panic listed
+// in [RecoveryPolicy.Fatal].
+func (p *RecoveryPolicy) isFatal(err Exception) bool {
+	if p == nil || len(err.GetCause()) == 0 {
+		return false
+	}
+	for _, fatal := range p.Fatal {
+		if errors.Is(err.GetCause()[0], fatal) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RecoveryPolicy) exit(err Exception) {
+	if p.Exit != nil {
+		p.Exit(err)
+		return
+	}
+	_ = WriteText(os.Stderr, err)
+	os.Exit(2)
+}
+
+func (p *RecoveryPolicy) recover(recovered any, skip int) Exception {
+	if recovered == nil {
+		return nil
+	}
+	if !p.IsRecoverable(recovered) {
+		panic(recovered)
+	}
+	if err, ok := recovered.(Exception); ok && err.GetType() == string(PanicError) {
+		return err
+	}
+	// skip to panic frame if exists
+	trace := StackTrace(skip + 1)
+	for i, frame := range trace {
+		if frame.Function == "runtime.gopanic" {
+			trace = trace[i+1:]
+			break
+		}
+	}
+	err := fullException{
+		Type:      string(PanicError),
+		Causes:    panicCause(recovered),
+		Recovered: recovered,
+		Stack:     trace,
+		ID:        NewID(),
+		Time:      time.Now(),
+	}
+	if p != nil && p.Snapshot {
+		err.Goroutines = snapshot(p.SnapshotSize)
+	}
+	if p.isFatal(err) {
+		p.exit(err)
+	}
+	return err
+}
+
+func (p *RecoveryPolicy) handleRecover(err *error, recovered any) {
+	if recovered != nil {
+		if checked, ok := handle(recovered); ok {
+			*err = checked
+		} else {
+			*err = p.recover(recovered, 2)
+		}
+	}
+}
+
+func (p *RecoveryPolicy) cleanup(recovered any, function func()) {
+	if !p.IsRecoverable(recovered) {
+		// re-panicked even if the function panics too
+		defer panic(recovered)
+		function()
+		return
+	}
+	previous := p.recover(recovered, 2)
+	current := p.cleanupCall(function)
+	switch {
+	case current != nil && previous != nil:
+		panic(current.AddSuppressed(previous))
+	case current != nil:
+		panic(current)
+	case previous != nil:
+		panic(previous)
+	}
+}
+
+func (p *RecoveryPolicy) cleanupCall(function func()) (err Exception) {
+	defer func() {
+		err = p.recover(recover(), 1)
+	}()
+	function()
+	return nil
+}
+
+// ========================================
+
+var recoveryPolicy atomic.Pointer[RecoveryPolicy]
+
+// SetRecoveryPolicy replaces the active [RecoveryPolicy] and returns the
+// previous one. A nil policy recovers every panic.
+//
+// The policy must not be modified after being set.
+func SetRecoveryPolicy(policy *RecoveryPolicy) (previous *RecoveryPolicy) {
+	return recoveryPolicy.Swap(policy)
+}
+
+// GetRecoveryPolicy returns the active [RecoveryPolicy], which may be nil.
+func GetRecoveryPolicy() *RecoveryPolicy {
+	return recoveryPolicy.Load()
+}
@@ -0,0 +1,142 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+var errAbort = errors.New("abort")
+
+type abortProcess struct{}
+
+func TestRecoveryPolicyNeverRecover(t *testing.T) {
+	policy := &exception.RecoveryPolicy{
+		Values: []any{errAbort},
+		Types:  []reflect.Type{reflect.TypeFor[abortProcess]()},
+	}
+	for _, value := range []any{errAbort, abortProcess{}} {
+		recovered := func() (recovered any) {
+			defer func() {
+				recovered = recover()
+			}()
+			var err error
+			defer policy.HandleRecover(&err)
+			panic(value)
+		}()
+		if recovered != value {
+			t.Errorf("Expected %#v to be re-panicked untouched but got %#v", value, recovered)
+		}
+	}
+	if !policy.IsRecoverable("Test") {
+		t.Errorf("Expected other values to be recoverable")
+	}
+}
+
+func TestRecoveryPolicyGlobal(t *testing.T) {
+	previous := exception.SetRecoveryPolicy(&exception.RecoveryPolicy{Values: []any{errAbort}})
+	defer exception.SetRecoveryPolicy(previous)
+	recovered := func() (recovered any) {
+		defer func() {
+			recovered = recover()
+		}()
+		defer exception.Cleanup(func() {})
+		panic(errAbort)
+	}()
+	if recovered != errAbort {
+		t.Errorf("Expected the abort error to be re-panicked untouched but got %#v", recovered)
+	}
+}
+
+func TestRecoveryPolicyFatal(t *testing.T) {
+	var fatal exception.Exception
+	policy := &exception.RecoveryPolicy{
+		Fatal: []exception.String{exception.NilPointerError},
+		Exit: func(err exception.Exception) {
+			fatal = err
+		},
+	}
+	func() {
+		defer func() {
+			_ = policy.Recover(recover())
+		}()
+		var pointer *int
+		_ = *pointer
+	}()
+	if !errors.Is(fatal, exception.NilPointerError) {
+		t.Errorf("Expected the nil pointer dereference to be fatal but got %#v", fatal)
+	}
+}
+
+func TestRecoveryPolicySnapshot(t *testing.T) {
+	policy := &exception.RecoveryPolicy{Snapshot: true}
+	blocked := make(chan struct{})
+	defer close(blocked)
+	go func() {
+		<-blocked
+	}()
+	var err exception.Exception
+	func() {
+		defer func() {
+			err = policy.Recover(recover())
+		}()
+		panic("Test")
+	}()
+	goroutines := err.GetGoroutines()
+	if len(goroutines) < 2 {
+		t.Fatalf("Expected a snapshot of several goroutines but got %#v", goroutines)
+	}
+	found := false
+	for _, goroutine := range goroutines {
+		found = found || strings.HasSuffix(goroutine.CreatedBy.Function, "/go-exception_test.TestRecoveryPolicySnapshot")
+	}
+	if !found {
+		t.Errorf("Expected the blocked goroutine in the snapshot but got %#v", goroutines)
+	}
+
+	var output bytes.Buffer
+	if err := exception.WriteText(&output, err); err != nil || !strings.Contains(output.String(), "\tgoroutines:\n") {
+		t.Errorf("Expected the snapshot to be rendered but got %s", output.String())
+	}
+}
+
+func TestRecoveryPolicyNeverRecoverPanic(t *testing.T) {
+	policy := &exception.RecoveryPolicy{Values: []any{errAbort}}
+	recovered := func() (recovered any) {
+		defer func() {
+			recovered = recover()
+		}()
+		defer policy.HandleRecover(new(error))
+		exception.Panic(errAbort)
+		return nil
+	}()
+	if err, ok := recovered.(exception.Exception); !ok || err.GetRecovered() != errAbort {
+		t.Errorf("Expected the exception wrapping the abort error to be re-panicked but got %#v", recovered)
+	}
+}
+
+func TestRecoveryPolicyCleanupPanics(t *testing.T) {
+	policy := &exception.RecoveryPolicy{Values: []any{errAbort}}
+	recovered := func() (recovered any) {
+		defer func() {
+			recovered = recover()
+		}()
+		defer policy.Cleanup(func() {
+			panic("cleanup")
+		})
+		panic(errAbort)
+	}()
+	if recovered != errAbort {
+		t.Errorf("Expected the abort error to be re-panicked untouched but got %#v", recovered)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"reflect"
+	"regexp"
+	"slices"
+	"sync/atomic"
+)
+
+// defaultMask is used in place of hidden values when [RedactionPolicy.Mask] is
+// empty.
+const defaultMask = "[REDACTED]"
+
+// RedactionPolicy controls which details of an [Exception] are hidden when it
+// is written out. A nil policy hides nothing. It is applied by every renderer
+// of this package:
+//
+//   - the zerolog marshallers
+//   - [WriteText] and [WriteJSON], and so the output of [Program]
+//   - the crash reports written by [CrashReporter]
+//
+// It is not applied by the Error method of an [Exception], nor by the fmt
+// verbs relying on it, since that string is also used to compare and wrap
+// errors. Use one of the renderers above to write an exception where it may
+// leak.
+//
+// The active policy is set with [SetRedactionPolicy]. Builds using the
+// "production" build tag start with a restrictive policy, other builds start
+// with no policy at all.
+type RedactionPolicy struct {
+	// Mask replaces every hidden value. When empty, "[REDACTED]" is used.
+	Mask string
+
+	// Keys lists output keys, such as "message" or "recovered", whose values are
+	// always replaced by the mask.
+	Keys []string
+
+	// Patterns lists regular expressions matched against messages. Every match is
+	// replaced by the mask.
+	Patterns []*regexp.Regexp
+
+	// Recovered lists the types of recovered values that may be written as-is.
+	// An interface type allows every value implementing it. Other recovered
+	// values are replaced by the mask followed by their type.
+	Recovered []reflect.Type
+}
+
+// IsMasked reports whether the value written under the given key must be
+// replaced entirely by the mask.
+func (p *RedactionPolicy) IsMasked(key string) bool {
+	return p != nil && slices.Contains(p.Keys, key)
+}
+
+// RedactMessage returns the message with every match of the policy patterns
+// replaced by the mask.
+func (p *RedactionPolicy) RedactMessage(message string) string {
+	if p == nil {
+		return message
+	}
+	for _, pattern := range p.Patterns {
+		message = pattern.ReplaceAllLiteralString(message, p.mask())
+	}
+	return message
+}
+
+// RedactRecovered returns the recovered value if its type is allowed by the
+// policy, otherwise a string containing the mask and the type of the value.
+//
+// An [Exception] is always allowed, since it applies the policy itself when
+// written out.
+func (p *RedactionPolicy) RedactRecovered(recovered any) any {
+	if p == nil || recovered == nil {
+		return recovered
+	}
+	if _, ok := recovered.(Exception); ok {
+		return recovered
+	}
+	recoveredType := reflect.TypeOf(recovered)
+	for _, allowed := range p.Recovered {
+		if recoveredType == allowed || allowed.Kind() == reflect.Interface && recoveredType.Implements(allowed) {
+			return recovered
+		}
+	}
+	return p.mask() + " " + recoveredType.String()
+}
+
+// RedactError returns the error unchanged if it is an [Exception], otherwise an
+// error whose message has been passed through [RedactionPolicy.RedactMessage].
+func (p *RedactionPolicy) RedactError(err error) error {
+	if p == nil || len(p.Patterns) == 0 || err == nil {
+		return err
+	}
+	if _, ok := err.(Exception); ok {
+		return err
+	}
+	return redactedError(p.RedactMessage(err.Error()))
+}
+
+func (p *RedactionPolicy) mask() string {
+	if p.Mask == "" {
+		return defaultMask
+	}
+	return p.Mask
+}
+
+// redactedError is the result of [RedactionPolicy.RedactError] on a foreign
+// error.
+type redactedError string
+
+func (e redactedError) Error() string {
+	return string(e)
+}
+
+// ========================================
+
+var redactionPolicy atomic.Pointer[RedactionPolicy]
+
+func init() {
+	redactionPolicy.Store(defaultRedactionPolicy())
+}
+
+// SetRedactionPolicy replaces the active [RedactionPolicy] and returns the
+// previous one. A nil policy disables redaction.
+//
+// The policy must not be modified after being set.
+func SetRedactionPolicy(policy *RedactionPolicy) (previous *RedactionPolicy) {
+	return redactionPolicy.Swap(policy)
+}
+
+// GetRedactionPolicy returns the active [RedactionPolicy], which may be nil.
+func GetRedactionPolicy() *RedactionPolicy {
+	return redactionPolicy.Load()
+}
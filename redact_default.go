@@ -0,0 +1,14 @@
+//go:build !production
+
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+// defaultRedactionPolicy hides nothing outside production builds.
+func defaultRedactionPolicy() *RedactionPolicy {
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build production
+
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+)
+
+// defaultRedactionPolicy scrubs common credentials and e-mail addresses from
+// messages and only lets numbers, booleans and runtime errors through as
+// recovered values.
+func defaultRedactionPolicy() *RedactionPolicy {
+	return &RedactionPolicy{
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9._~+/-]+=*`),
+			regexp.MustCompile(`(?i)\b(password|passwd|secret|token|api[_-]?key)\s*[=:]\s*\S+`),
+			regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`),
+		},
+		Recovered: []reflect.Type{
+			reflect.TypeFor[bool](),
+			reflect.TypeFor[int](),
+			reflect.TypeFor[int8](),
+			reflect.TypeFor[int16](),
+			reflect.TypeFor[int32](),
+			reflect.TypeFor[int64](),
+			reflect.TypeFor[uint](),
+			reflect.TypeFor[uint8](),
+			reflect.TypeFor[uint16](),
+			reflect.TypeFor[uint32](),
+			reflect.TypeFor[uint64](),
+			reflect.TypeFor[float32](),
+			reflect.TypeFor[float64](),
+			reflect.TypeFor[runtime.Error](),
+		},
+	}
+}
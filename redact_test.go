@@ -0,0 +1,68 @@
+//go:build !no_zerolog
+
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/thanhminhmr/go-exception"
+)
+
+func logObject(object zerolog.LogObjectMarshaler) string {
+	var buffer bytes.Buffer
+	logger := zerolog.New(&buffer)
+	logger.Log().EmbedObject(object).Send()
+	return buffer.String()
+}
+
+func TestRedactionPolicy(t *testing.T) {
+	previous := exception.SetRedactionPolicy(&exception.RedactionPolicy{
+		Keys:      []string{"suppressed"},
+		Patterns:  []*regexp.Regexp{regexp.MustCompile(`token=\S+`)},
+		Recovered: []reflect.Type{reflect.TypeFor[int]()},
+	})
+	defer exception.SetRedactionPolicy(previous)
+
+	err := exception.String("Test: login with token=abc").
+		AddCause(errors.New("bad token=xyz")).
+		AddSuppressed(errors.New("secret")).
+		SetRecovered("password")
+	output := logObject(err.(zerolog.LogObjectMarshaler))
+	for _, leaked := range []string{"abc", "xyz", "secret", "password"} {
+		if strings.Contains(output, leaked) {
+			t.Errorf("Expected \"%s\" to be redacted but got %s", leaked, output)
+		}
+	}
+	if !strings.Contains(output, "[REDACTED] string") {
+		t.Errorf("Expected recovered value to be replaced but got %s", output)
+	}
+
+	output = logObject(exception.String("Test").SetRecovered(42).(zerolog.LogObjectMarshaler))
+	if !strings.Contains(output, `"recovered":42`) {
+		t.Errorf("Expected allowed recovered value to be kept but got %s", output)
+	}
+}
+
+func TestRedactionPolicyString(t *testing.T) {
+	previous := exception.SetRedactionPolicy(&exception.RedactionPolicy{
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`token=\S+`)},
+	})
+	defer exception.SetRedactionPolicy(previous)
+
+	output := logObject(exception.String("Test: token=abc"))
+	if !strings.Contains(output, `"error":"Test: [REDACTED]"`) {
+		t.Errorf("Expected message to be redacted but got %s", output)
+	}
+}
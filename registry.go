@@ -0,0 +1,103 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// Definition describes an exception type for the callers of an API: how it
+// maps to transport status codes, how severe it is and whether the failed
+// operation may be retried. Definitions are usually generated from a catalog
+// by the exceptiongen command and registered with [Register].
+type Definition struct {
+	// Type is the type of the exceptions this definition applies to.
+	Type string
+
+	// Message is the message template of the exceptions, without the type.
+	Message string
+
+	// HTTPStatus is the HTTP status code of the exceptions, or 0 if unspecified.
+	HTTPStatus int
+
+	// GRPCCode is the name of the gRPC status code of the exceptions, such as
+	// "NotFound", or empty if unspecified.
+	GRPCCode string
+
+	// Severity is the severity of the exceptions, such as "error" or "warning".
+	Severity string
+
+	// Retryable reports whether the failed operation may be retried as-is.
+	Retryable bool
+
+	// Doc is the documentation of the exceptions.
+	Doc string
+}
+
+var registry = struct {
+	sync.RWMutex
+	definitions map[string]Definition
+}{definitions: map[string]Definition{}}
+
+// Register adds the definitions to the registry. It panics if a type is
+// registered twice, since two packages declaring the same type cannot be told
+// apart by [errors.Is].
+func Register(definitions ...Definition) {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, definition := range definitions {
+		if _, ok := registry.definitions[definition.Type]; ok {
+			panic("exception: type registered twice: " + definition.Type)
+		}
+		registry.definitions[definition.Type] = definition
+	}
+}
+
+// Lookup returns the registered definition of the error. The type of the
+// error is looked up first, then the types of its causes, depth first.
+func Lookup(err error) (Definition, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	return lookup(err)
+}
+
+func lookup(err error) (Definition, bool) {
+	if exception, ok := err.(Exception); ok {
+		if definition, ok := registry.definitions[exception.GetType()]; ok {
+			return definition, true
+		}
+	}
+	switch unwrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := unwrapped.Unwrap(); cause != nil {
+			return lookup(cause)
+		}
+	case interface{ Unwrap() []error }:
+		for _, cause := range unwrapped.Unwrap() {
+			if definition, ok := lookup(cause); ok {
+				return definition, true
+			}
+		}
+	}
+	return Definition{}, false
+}
+
+// Definitions returns every registered definition, sorted by type.
+func Definitions() []Definition {
+	registry.RLock()
+	defer registry.RUnlock()
+	definitions := make([]Definition, 0, len(registry.definitions))
+	for _, definition := range registry.definitions {
+		definitions = append(definitions, definition)
+	}
+	slices.SortFunc(definitions, func(a, b Definition) int {
+		return cmp.Compare(a.Type, b.Type)
+	})
+	return definitions
+}
@@ -0,0 +1,52 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+const AccountLockedError = exception.String("AccountLocked: too many attempts")
+
+func init() {
+	exception.Register(exception.Definition{
+		Type:       "AccountLocked",
+		Message:    "too many attempts",
+		HTTPStatus: 423,
+		Retryable:  true,
+	})
+}
+
+func TestLookup(t *testing.T) {
+	wrapped := fmt.Errorf("login: %w", exception.Join(UsageError, AccountLockedError.FillStackTrace(0)))
+	definition, ok := exception.Lookup(wrapped)
+	if !ok || definition.HTTPStatus != 423 || !definition.Retryable {
+		t.Errorf("Expected the definition of the cause but got %#v", definition)
+	}
+	if _, ok := exception.Lookup(UsageError); ok {
+		t.Errorf("Expected no definition for an unregistered type")
+	}
+	found := false
+	for _, definition := range exception.Definitions() {
+		found = found || definition.Type == "AccountLocked"
+	}
+	if !found {
+		t.Errorf("Expected the registered definition to be listed")
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected registering a type twice to panic")
+		}
+	}()
+	exception.Register(exception.Definition{Type: "AccountLocked"})
+}
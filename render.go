@@ -0,0 +1,249 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteText renders the error in a human-friendly text form, similar to a Java
+// stack trace: the error itself, its instance ID and creation time, its
+// recovered value and its stack trace, followed by its causes and suppressed
+// errors, each one indented under the error it belongs to. The creation time
+// of a suppressed exception is followed by its offset from the creation time
+// of the exception suppressing it, such as "+12ms". The stack traces of errors
+// from other libraries are found with [StackTraceOf]. The active
+// [RedactionPolicy] is applied.
+func WriteText(writer io.Writer, err error) error {
+	text := textWriter{writer: writer, policy: GetRedactionPolicy()}
+	text.write("", "", time.Time{}, err)
+	return text.err
+}
+
+type textWriter struct {
+	writer io.Writer
+	policy *RedactionPolicy
+	err    error
+}
+
+func (w *textWriter) printf(format string, parameters ...any) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.writer, format, parameters...)
+	}
+}
+
+// write renders the error under the given label. The parent time is the
+// creation time of the exception the error is suppressed by, or the zero time.
+func (w *textWriter) write(indent string, label string, parent time.Time, err error) {
+	exception, ok := err.(Exception)
+	if !ok {
+		w.printf("%s%s%s\n", indent, label, w.policy.RedactError(err).Error())
+		w.writeStackTrace(indent, StackTraceOf(err))
+		return
+	}
+	w.printf("%s%s%s\n", indent, label, redactedErrorString(w.policy, exception))
+	if id := exception.GetID(); id != "" {
+		created := exception.GetTime()
+		if parent.IsZero() || created.IsZero() {
+			w.printf("%s\tid: %s (%s)\n", indent, id, created.Format(time.RFC3339Nano))
+		} else {
+			w.printf("%s\tid: %s (%s, %s)\n", indent, id, created.Format(time.RFC3339Nano), offset(created.Sub(parent)))
+		}
+	}
+	if recovered := exception.GetRecovered(); recovered != nil {
+		if w.policy.IsMasked("recovered") {
+			recovered = w.policy.mask()
+		} else {
+			recovered = w.policy.RedactRecovered(recovered)
+		}
+		w.printf("%s\trecovered: %v\n", indent, recovered)
+	}
+	w.writeStackTrace(indent, exception.GetStackTrace())
+	if goroutines := exception.GetGoroutines(); goroutines != nil {
+		w.printf("%s\tgoroutines:\n", indent)
+		for _, goroutine := range goroutines {
+			w.printf("%s\t\tgoroutine %d [%s]", indent, goroutine.ID, goroutine.State)
+			if goroutine.Wait != 0 {
+				w.printf(" waiting for %s", goroutine.Wait)
+			}
+			w.printf("\n")
+			for _, frame := range goroutine.StackTrace {
+				w.printf("%s\t\t\tat %s (%s:%d)\n", indent, frame.Function, frame.File, frame.Line)
+			}
+			if frame := goroutine.CreatedBy; frame.Function != "" {
+				w.printf("%s\t\t\tcreated by %s (%s:%d)\n", indent, frame.Function, frame.File, frame.Line)
+			}
+		}
+	}
+	if !w.policy.IsMasked("cause") {
+		for _, cause := range exception.GetCause() {
+			w.write(indent+"\t", "caused by: ", time.Time{}, cause)
+		}
+	}
+	if !w.policy.IsMasked("suppressed") {
+		for _, suppressed := range exception.GetSuppressed() {
+			w.write(indent+"\t", "suppressed: ", exception.GetTime(), suppressed)
+		}
+	}
+}
+
+// offset returns the duration with an explicit sign, such as "+12ms".
+func offset(duration time.Duration) string {
+	if duration < 0 {
+		return duration.String()
+	}
+	return "+" + duration.String()
+}
+
+func (w *textWriter) writeStackTrace(indent string, trace StackFrames) {
+	for _, frame := range trace {
+		w.printf("%s\tat %s (%s:%d)\n", indent, frame.Function, frame.File, frame.Line)
+	}
+}
+
+// redactedErrorString returns the string representation of the exception with
+// its message redacted.
+func redactedErrorString(policy *RedactionPolicy, exception Exception) string {
+	message := exception.GetMessage()
+	if message == "" {
+		// the causes of a type-less exception are rendered on their own
+		return exception.GetType()
+	}
+	if policy == nil {
+		return exception.Error()
+	}
+	if policy.IsMasked("message") {
+		message = policy.mask()
+	} else {
+		message = policy.RedactMessage(message)
+	}
+	if exception.GetType() == "" {
+		return message
+	}
+	return exception.GetType() + separator + message
+}
+
+// ========================================
+
+// WriteJSON renders the error as a single line of JSON, using the same keys as
+// the zerolog marshallers. An error from another library is rendered as its
+// message, or as an object with an empty type when it carries a stack trace
+// (see [StackTraceOf]). The active [RedactionPolicy] is applied.
+func WriteJSON(writer io.Writer, err error) error {
+	return json.NewEncoder(writer).Encode(jsonValue(GetRedactionPolicy(), err))
+}
+
+func jsonValue(policy *RedactionPolicy, err error) any {
+	exception, ok := err.(Exception)
+	if !ok {
+		message := policy.RedactError(err).Error()
+		if trace := StackTraceOf(err); trace != nil {
+			// a foreign error with a stack trace has no type
+			return map[string]any{"error": "", "message": jsonMasked(policy, "message", message), "stack_trace": jsonStackTrace(trace)}
+		}
+		return message
+	}
+	object := map[string]any{"error": exception.GetType()}
+	if id := exception.GetID(); id != "" {
+		object["id"] = id
+		object["created_at"] = exception.GetTime()
+	}
+	if message := exception.GetMessage(); message != "" {
+		object["message"] = jsonMasked(policy, "message", policy.RedactMessage(message))
+	}
+	if cause := jsonErrors(policy, exception.GetCause()); cause != nil {
+		object["cause"] = jsonMasked(policy, "cause", cause)
+	}
+	if suppressed := jsonErrors(policy, exception.GetSuppressed()); suppressed != nil {
+		object["suppressed"] = jsonMasked(policy, "suppressed", suppressed)
+	}
+	if recovered := exception.GetRecovered(); recovered != nil {
+		object["recovered"] = jsonMasked(policy, "recovered", jsonRecovered(policy.RedactRecovered(recovered)))
+	}
+	if carrier, ok := exception.(payloadCarrier); ok {
+		object["payload"] = jsonMasked(policy, "payload", carrier.payload())
+	}
+	if trace := exception.GetStackTrace(); trace != nil {
+		object["stack_trace"] = jsonStackTrace(trace)
+	}
+	if goroutines := exception.GetGoroutines(); goroutines != nil {
+		object["goroutines"] = jsonGoroutines(policy, goroutines)
+	}
+	return object
+}
+
+func jsonMasked(policy *RedactionPolicy, key string, value any) any {
+	if policy.IsMasked(key) {
+		return policy.mask()
+	}
+	return value
+}
+
+func jsonErrors(policy *RedactionPolicy, errors []error) any {
+	switch len(errors) {
+	case 0:
+		return nil
+	case 1:
+		return jsonValue(policy, errors[0])
+	default:
+		values := make([]any, len(errors))
+		for i, err := range errors {
+			values[i] = jsonValue(policy, err)
+		}
+		return values
+	}
+}
+
+// jsonRecovered returns the recovered value itself if it can be encoded as
+// JSON, otherwise its string representation.
+func jsonRecovered(recovered any) any {
+	if err, ok := recovered.(error); ok {
+		return err.Error()
+	}
+	if _, err := json.Marshal(recovered); err != nil {
+		return fmt.Sprint(recovered)
+	}
+	return recovered
+}
+
+func jsonStackTrace(trace StackFrames) []map[string]any {
+	frames := make([]map[string]any, len(trace))
+	for i, frame := range trace {
+		frames[i] = map[string]any{
+			"function": frame.Function,
+			"file":     frame.File,
+			"line":     frame.Line,
+		}
+	}
+	return frames
+}
+
+func jsonGoroutines(policy *RedactionPolicy, goroutines []Goroutine) []map[string]any {
+	values := make([]map[string]any, len(goroutines))
+	for i, goroutine := range goroutines {
+		value := map[string]any{
+			"id":          goroutine.ID,
+			"state":       goroutine.State,
+			"stack_trace": jsonStackTrace(goroutine.StackTrace),
+		}
+		if goroutine.Wait != 0 {
+			value["wait"] = goroutine.Wait.String()
+		}
+		if frame := goroutine.CreatedBy; frame.Function != "" {
+			value["created_by"] = jsonStackTrace(StackFrames{frame})[0]
+			value["creator_id"] = goroutine.CreatorID
+		}
+		if goroutine.Exception != nil {
+			value["exception"] = jsonValue(policy, goroutine.Exception)
+		}
+		values[i] = value
+	}
+	return values
+}
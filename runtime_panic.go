@@ -0,0 +1,99 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"errors"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Types of the runtime panics recognized by [Panic] and [Recover]. When a
+// recovered value is a [runtime.Error] of one of these classes, the resulting
+// [Exception] keeps [PanicError] as its type and gets the class as its first
+// cause, so it matches both:
+//
+//	if errors.Is(err, exception.NilPointerError) {
+//	    // err also matches exception.PanicError
+//	}
+const (
+	NilPointerError      = String("nil pointer dereference")
+	IndexOutOfRangeError = String("index out of range")
+	NilMapError          = String("assignment to entry in nil map")
+	DivideByZeroError    = String("integer divide by zero")
+	TypeAssertionError   = String("type assertion failed")
+	ClosedChannelError   = String("send on closed channel")
+	NilPanicError        = String("panic with nil argument")
+)
+
+// IndexOutOfRange is the payload of an [IndexOutOfRangeError], holding the
+// index and the length reported by the runtime.
+type IndexOutOfRange struct {
+	Index  int
+	Length int
+}
+
+// indexOutOfRange matches the message of an index out of range runtime error.
+var indexOutOfRange = regexp.MustCompile(`index out of range \[(-?\d+)\] with length (\d+)`)
+
+// classifyPanic returns the class of a recovered runtime panic, or nil if the
+// recovered value is not a recognized [runtime.Error].
+func classifyPanic(recovered any) Exception {
+	err, ok := recovered.(runtime.Error)
+	if !ok {
+		return nil
+	}
+	var panicNil *runtime.PanicNilError
+	var typeAssertion *runtime.TypeAssertionError
+	switch {
+	case errors.As(err, &panicNil):
+		return NilPanicError
+	case errors.As(err, &typeAssertion):
+		return TypeAssertionError + separator + String(err.Error())
+	default:
+		return classifyMessage(err.Error())
+	}
+}
+
+// classifyMessage returns the class of a runtime panic from its message, as
+// printed by the runtime, or nil if the message is not recognized.
+func classifyMessage(message string) Exception {
+	switch {
+	case strings.HasPrefix(message, "panic called with nil argument"):
+		return NilPanicError
+	case strings.HasPrefix(message, "interface conversion: "):
+		return TypeAssertionError + separator + String(message)
+	case strings.HasSuffix(message, "nil pointer dereference"):
+		return NilPointerError
+	case strings.Contains(message, "index out of range"):
+		if match := indexOutOfRange.FindStringSubmatch(message); match != nil {
+			index, _ := strconv.Atoi(match[1])
+			length, _ := strconv.Atoi(match[2])
+			return TypedFrom(IndexOutOfRangeError, IndexOutOfRange{Index: index, Length: length}).SetMessage("%s", message)
+		}
+		return IndexOutOfRangeError + separator + String(message)
+	case strings.HasSuffix(message, "assignment to entry in nil map"):
+		return NilMapError
+	case strings.HasSuffix(message, "integer divide by zero"):
+		return DivideByZeroError
+	case strings.HasSuffix(message, "send on closed channel"):
+		return ClosedChannelError
+	default:
+		return nil
+	}
+}
+
+// panicCause returns the causes of an [Exception] created for a recovered
+// value, which hold its class if it is a recognized runtime panic.
+func panicCause(recovered any) []error {
+	if class := classifyPanic(recovered); class != nil {
+		return []error{class}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+func recoverFrom(function func()) (err exception.Exception) {
+	defer func() {
+		err = exception.Recover(recover())
+	}()
+	function()
+	return nil
+}
+
+func TestRecoverRuntimePanics(t *testing.T) {
+	var pointer *int
+	var dictionary map[string]int
+	var value any = "string"
+	zero := 0
+	index := 5
+	channel := make(chan int)
+	close(channel)
+	tests := []struct {
+		name     string
+		function func()
+		expected exception.String
+	}{
+		{"NilPointer", func() { _ = *pointer }, exception.NilPointerError},
+		{"IndexOutOfRange", func() { _ = []int{1, 2, 3}[index] }, exception.IndexOutOfRangeError},
+		{"NilMap", func() { dictionary["key"] = 1 }, exception.NilMapError},
+		{"DivideByZero", func() { _ = 1 / zero }, exception.DivideByZeroError},
+		{"TypeAssertion", func() { _ = value.(int) }, exception.TypeAssertionError},
+		{"ClosedChannel", func() { channel <- 1 }, exception.ClosedChannelError},
+		{"NilPanic", func() { panic(nil) }, exception.NilPanicError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := recoverFrom(test.function)
+			if !errors.Is(err, exception.PanicError) {
+				t.Errorf("Expected to match PanicError but got %#v", err)
+			}
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Expected to match \"%s\" but got %#v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestRecoverIndexOutOfRangeDetails(t *testing.T) {
+	index := 5
+	err := recoverFrom(func() { _ = []int{1, 2, 3}[index] })
+	details, ok := exception.PayloadOf[exception.IndexOutOfRange](err)
+	if !ok || details.Index != 5 || details.Length != 3 {
+		t.Errorf("Expected index 5 and length 3 but got %#v", details)
+	}
+}
+
+func TestRecoverPlainPanicNotClassified(t *testing.T) {
+	err := recoverFrom(func() { panic("Test") })
+	if len(err.GetCause()) != 0 {
+		t.Errorf("Expected no cause but got %#v", err.GetCause())
+	}
+}
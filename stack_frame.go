@@ -16,6 +16,10 @@ type StackFrame struct {
 	Function string
 	File     string
 	Line     int
+
+	// PC is the program counter of the frame, as in [runtime.Frame]. It is zero
+	// when the frame was parsed from text, such as a goroutine dump.
+	PC uintptr
 }
 
 // StackFrames is a slice of [StackFrame] values. It represents a complete stack
@@ -42,6 +46,7 @@ func StackTrace(skip int) StackFrames {
 				Function: frame.Function,
 				File:     frame.File,
 				Line:     frame.Line,
+				PC:       frame.PC,
 			})
 		} else {
 			break
@@ -7,8 +7,8 @@
 package exception
 
 import (
-	"fmt"
 	"strings"
+	"time"
 )
 
 // separator between type and message
@@ -63,7 +63,9 @@ func (e String) GetMessage() string {
 	return m
 }
 
-// SetMessage stores a message inside this exception.
+// SetMessage stores a message inside this exception. The type is kept and
+// separated from the new message by the separator sequence, so that the
+// returned [String] has the same type as this one.
 //
 // Note: This method may modify the current exception or return a new one. Always
 // use the returned [Exception].
@@ -72,9 +74,13 @@ func (e String) SetMessage(message string, parameters ...any) Exception {
 	case message == "":
 		return e
 	case len(parameters) == 0:
-		return String(e.GetType() + "\n" + message)
+		return String(e.GetType() + separator + message)
 	default:
-		return String(e.GetType() + "\n" + fmt.Sprintf(message, parameters...))
+		formatted, wrapped := formatMessage(message, parameters)
+		if len(wrapped) > 0 {
+			return String(e.GetType() + separator + formatted).AddCause(wrapped...)
+		}
+		return String(e.GetType() + separator + formatted)
 	}
 }
 
@@ -96,7 +102,7 @@ func (e String) AddCause(errors ...error) Exception {
 		return fullException{
 			Type:    e.GetType(),
 			Message: e.GetMessage(),
-			Cause:   cause,
+			Causes:  cause,
 		}
 	}
 	return e
@@ -162,13 +168,38 @@ func (e String) GetStackTrace() StackFrames {
 // Note: This method may modify the current exception or return a new one. Always
 // use the returned [Exception].
 func (e String) FillStackTrace(skip int) Exception {
+	return e.withStackTrace(StackTrace(skip + 1))
+}
+
+func (e String) withStackTrace(trace StackFrames) Exception {
 	return fullException{
-		Type:       e.GetType(),
-		Message:    e.GetMessage(),
-		StackTrace: StackTrace(skip + 1),
+		Type:    e.GetType(),
+		Message: e.GetMessage(),
+		Stack:   trace,
+		ID:      NewID(),
+		Time:    time.Now(),
 	}
 }
 
+// GetID returns the unique instance ID of this exception. A [String] has no ID,
+// so the result is always empty.
+func (e String) GetID() string {
+	return ""
+}
+
+// GetTime returns the time this exception was created. A [String] has no
+// creation time, so the result is always the zero time.
+func (e String) GetTime() time.Time {
+	return time.Time{}
+}
+
+// GetGoroutines returns the snapshot of all goroutines captured when this
+// exception was recovered. A [String] has no snapshot, so the result is always
+// nil.
+func (e String) GetGoroutines() []Goroutine {
+	return nil
+}
+
 func (e String) __() {}
 
 func (e String) Is(target error) bool {
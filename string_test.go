@@ -63,3 +63,30 @@ func TestStringEmpty(t *testing.T) {
 		t.Errorf("Expected to have empty error string but got \"%s\"", StringError.Error())
 	}
 }
+
+func TestStringSetMessage(t *testing.T) {
+	const StringError = exception.String("Test: Message")
+	err := StringError.SetMessage("Replaced %d", 1)
+	if err.GetType() != "Test" || err.GetMessage() != "Replaced 1" {
+		t.Errorf("Expected type \"Test\" and message \"Replaced 1\" but got %q and %q", err.GetType(), err.GetMessage())
+	}
+}
+
+func TestStringSetMessageSeparator(t *testing.T) {
+	for _, test := range []struct {
+		err      exception.Exception
+		expected exception.String
+	}{
+		{exception.String("Test").SetMessage("Message"), "Test: Message"},
+		{exception.String("Test: Old").SetMessage("Message %s", "New"), "Test: Message New"},
+		{exception.String("").SetMessage("Message"), ": Message"},
+	} {
+		if test.err != test.expected {
+			t.Errorf("Expected %q but got %#v", test.expected, test.err)
+		}
+		if test.err.GetType() != test.expected.GetType() || test.err.Error() != test.expected.Error() {
+			t.Errorf("Expected type %q and error string %q but got %q and %q",
+				test.expected.GetType(), test.expected.Error(), test.err.GetType(), test.err.Error())
+		}
+	}
+}
@@ -6,8 +6,6 @@
 
 package exception
 
-import "fmt"
-
 // Template represents a reusable message pattern for creating [String]
 // exceptions. It behaves like a format string that can be expanded with
 // parameters to produce consistent exception messages.
@@ -18,7 +16,32 @@ import "fmt"
 type Template string
 
 // Format applies the given parameters to this template using [fmt.Sprintf] and
-// returns a new [String] containing the formatted message.
+// returns a new [String] containing the formatted message. As with
+// [fmt.Errorf], %w verbs are formatted like %v; use [Template.Wrap] to also
+// attach the errors given to them as causes.
 func (t Template) Format(parameters ...any) String {
-	return String(fmt.Sprintf(string(t), parameters...))
+	message, _ := formatMessage(string(t), parameters)
+	return String(message)
+}
+
+// Wrap applies the given parameters to this template like [Template.Format]
+// and attaches the errors given to %w verbs as causes of the resulting
+// exception.
+//
+//	const FileIOError = exception.Template("IOError: %s failed: %w")
+//
+//	err := FileIOError.Wrap(name, err)
+func (t Template) Wrap(parameters ...any) Exception {
+	message, wrapped := formatMessage(string(t), parameters)
+	return String(message).AddCause(wrapped...)
+}
+
+// Errorf creates an exception of the given type with a message formatted like
+// [fmt.Errorf]: %w verbs are formatted like %v and the errors given to them
+// are attached as causes. The message of the given [String], if any, is
+// replaced.
+//
+//	err := exception.Errorf(IOError, "read %s: %w", name, err)
+func Errorf(source String, format string, parameters ...any) Exception {
+	return source.SetMessage(format, parameters...)
 }
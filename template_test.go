@@ -0,0 +1,84 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+const FileError = exception.Template("IOError: %s failed")
+
+func TestTemplateFormat(t *testing.T) {
+	err := FileError.Format("read")
+	if err != "IOError: read failed" || err.GetType() != "IOError" || err.GetMessage() != "read failed" {
+		t.Errorf("Expected the formatted String but got %q", err)
+	}
+	// %w verbs are formatted like %v
+	if err := exception.Template("IOError: %s: %w").Format("x", io.EOF); err != "IOError: x: EOF" {
+		t.Errorf("Expected the %%w verb to be formatted like %%v but got %q", err)
+	}
+}
+
+func TestTemplateWrap(t *testing.T) {
+	const WrappedFileError = exception.Template("IOError: %s failed: %w")
+	err := WrappedFileError.Wrap("read", io.EOF)
+	if err.GetType() != "IOError" || err.GetMessage() != "read failed: EOF" {
+		t.Errorf("Expected the %%w verb to be formatted like %%v but got %q", err.Error())
+	}
+	if causes := err.GetCause(); len(causes) != 1 || causes[0] != io.EOF || !errors.Is(err, io.EOF) {
+		t.Errorf("Expected the wrapped error as cause but got %v", causes)
+	}
+	// a nil error is formatted but not attached
+	if err := WrappedFileError.Wrap("read", nil); err != exception.String("IOError: read failed: <nil>") {
+		t.Errorf("Expected a String without cause but got %#v", err)
+	}
+}
+
+func TestSetMessageWrapsCauses(t *testing.T) {
+	for _, err := range []exception.Exception{
+		CloseError,
+		CloseError.FillStackTrace(0),
+		exception.Join(ParseError),
+		NewOrderFailure("42"),
+	} {
+		err = err.SetMessage("%[2]s then %[1]w and %[3]w", io.EOF, "open", os.ErrClosed)
+		if err.GetMessage() != "open then EOF and file already closed" {
+			t.Errorf("Expected the formatted message but got %q", err.GetMessage())
+		}
+		if !errors.Is(err, io.EOF) || !errors.Is(err, os.ErrClosed) {
+			t.Errorf("Expected the wrapped errors as causes but got %v", err.GetCause())
+		}
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	err := exception.Errorf(ParseError, "line %d: %w", 3, io.ErrUnexpectedEOF)
+	if !errors.Is(err, ParseError) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected the type and the cause of %#v", err)
+	}
+	if err.GetMessage() != "line 3: unexpected EOF" {
+		t.Errorf("Expected the formatted message but got %q", err.GetMessage())
+	}
+	// without a type, the message is the same as with fmt.Errorf
+	expected := fmt.Errorf("open %q: %w", "name", io.ErrUnexpectedEOF)
+	if err := exception.Errorf("", "open %q: %w", "name", io.ErrUnexpectedEOF); err.Error() != expected.Error() {
+		t.Errorf("Expected %q but got %q", expected.Error(), err.Error())
+	}
+}
+
+func TestTypedFromFormat(t *testing.T) {
+	err := exception.TypedFrom(FileError.Format("write").AddCause(io.ErrShortWrite), QuotaExceeded{Limit: 1})
+	if err.GetType() != "IOError" || !errors.Is(err, io.ErrShortWrite) || err.GetPayload().Limit != 1 {
+		t.Errorf("Expected the type, the cause and the payload of %#v", err)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Typed is an [Exception] carrying a strongly typed payload, giving
+// machine-readable details without parsing the message:
+//
+//	type QuotaExceeded struct {
+//	    Limit int
+//	}
+//
+//	err := exception.NewTyped(QuotaExceeded{Limit: 100})
+//
+//	...
+//
+//	if quota, ok := exception.PayloadOf[QuotaExceeded](err); ok {
+//	    // use quota.Limit
+//	}
+//
+// Like other exceptions built on [Base], methods that modify a [Typed] return a
+// modified copy that keeps the payload.
+type Typed[T any] struct {
+	Base
+	Payload T
+}
+
+// NewTyped creates a [Typed] exception carrying the payload, using the Go type
+// name of T as its type.
+func NewTyped[T any](payload T) *Typed[T] {
+	payloadType := reflect.TypeFor[T]()
+	name := payloadType.Name()
+	if name == "" {
+		name = payloadType.String()
+	}
+	return TypedFrom(String(name), payload)
+}
+
+// TypedFrom creates a [Typed] exception carrying the payload, using the type,
+// the message and the causes of the given source, usually a [String] or the
+// result of [Template.Format].
+func TypedFrom[T any](source Exception, payload T) *Typed[T] {
+	err := &Typed[T]{Payload: payload}
+	err.Init(err, source)
+	return err
+}
+
+// GetPayload returns the payload of this exception.
+func (e *Typed[T]) GetPayload() T {
+	return e.Payload
+}
+
+// payloadCarrier is implemented by every [Typed] exception, whatever its
+// payload type.
+type payloadCarrier interface {
+	payload() any
+}
+
+func (e *Typed[T]) payload() any {
+	return e.Payload
+}
+
+// PayloadOf searches the error and its causes for the first exception carrying
+// a payload of type T, in the same order as [errors.As], and returns that
+// payload.
+func PayloadOf[T any](err error) (T, bool) {
+	var carrier interface{ GetPayload() T }
+	if errors.As(err, &carrier) {
+		return carrier.GetPayload(), true
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,45 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+type QuotaExceeded struct {
+	Limit int
+}
+
+func TestTypedType(t *testing.T) {
+	err := exception.NewTyped(QuotaExceeded{Limit: 100})
+	if err.GetType() != "QuotaExceeded" {
+		t.Errorf("Expected to have type \"QuotaExceeded\" but got \"%s\"", err.GetType())
+	}
+	const QuotaError = exception.String("QuotaError: quota exceeded")
+	err = exception.TypedFrom(QuotaError, QuotaExceeded{Limit: 100})
+	if err.Error() != "QuotaError: quota exceeded" {
+		t.Errorf("Expected to have error string \"QuotaError: quota exceeded\" but got \"%s\"", err.Error())
+	}
+	if !errors.Is(err.FillStackTrace(0), QuotaError) {
+		t.Errorf("Expected filled exception to match QuotaError")
+	}
+}
+
+func TestPayloadOf(t *testing.T) {
+	cause := exception.NewTyped(QuotaExceeded{Limit: 100}).SetMessage("too many requests")
+	err := exception.Join(errors.New("other"), fmt.Errorf("wrapped: %w", cause))
+	if quota, ok := exception.PayloadOf[QuotaExceeded](err); !ok || quota.Limit != 100 {
+		t.Errorf("Expected to find payload with limit 100 but got %#v", quota)
+	}
+	if _, ok := exception.PayloadOf[int](err); ok {
+		t.Errorf("Expected to find no int payload")
+	}
+}
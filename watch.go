@@ -0,0 +1,89 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"time"
+)
+
+// TimeoutError is the type of the exception returned by [Watch] when the
+// function is still running after the deadline.
+const TimeoutError = String("timeout")
+
+// Timeout is the payload of a [TimeoutError] exception.
+type Timeout struct {
+	// GoroutineID is the ID of the goroutine still running the function.
+	GoroutineID int
+
+	// Elapsed is the time elapsed since the function was started.
+	Elapsed time.Duration
+}
+
+// Watch runs the function in a new goroutine and waits for it to return.
+//
+// If the function is still running after the deadline, [Watch] returns an
+// [Exception] of type [TimeoutError] without waiting any longer. Its stack
+// trace is the current stack of the goroutine running the function, showing
+// where it is stuck, and its payload is a [Timeout]. The goroutine is not
+// killed, but the context passed to the function is cancelled with the
+// exception as its cause (see [context.Cause]), and the result of the function
+// is discarded.
+//
+// A panic in the function is recovered with [HandleRecover] and returned as an
+// error.
+func Watch(ctx context.Context, deadline time.Duration, function func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	start := time.Now()
+	goroutineID := make(chan int, 1)
+	result := make(chan error, 1)
+	go func() {
+		goroutineID <- currentGoroutineID()
+		result <- watched(ctx, function)
+	}()
+	timer := time.NewTimer(deadline)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		cancel(nil)
+		return err
+	case <-timer.C:
+		err := timeout(<-goroutineID, time.Since(start))
+		cancel(err)
+		return err
+	}
+}
+
+func watched(ctx context.Context, function func(ctx context.Context) error) (err error) {
+	defer HandleRecover(&err)
+	return function(ctx)
+}
+
+// timeout returns the exception of a watched function still running in the
+// goroutine, with the current stack of that goroutine.
+func timeout(goroutineID int, elapsed time.Duration) Exception {
+	err := TypedFrom(TimeoutError, Timeout{GoroutineID: goroutineID, Elapsed: elapsed}).
+		SetMessage("still running after %s", elapsed)
+	for _, goroutine := range snapshot(0) {
+		if goroutine.ID == goroutineID {
+			return err.(stackTraceAttacher).withStackTrace(goroutine.StackTrace)
+		}
+	}
+	return err
+}
+
+// currentGoroutineID returns the ID of the calling goroutine, read from the
+// header of its own stack trace.
+func currentGoroutineID() int {
+	var buffer [64]byte
+	length := runtime.Stack(buffer[:], false)
+	line, _, _ := bytes.Cut(buffer[:length], []byte("\n"))
+	goroutine, _ := parseGoroutineHeader(string(line))
+	return goroutine.ID
+}
@@ -0,0 +1,54 @@
+/*
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ */
+
+package exception_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thanhminhmr/go-exception"
+)
+
+func stuck(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestWatchTimeout(t *testing.T) {
+	err := exception.Watch(context.Background(), 10*time.Millisecond, stuck)
+	if !errors.Is(err, exception.TimeoutError) {
+		t.Fatalf("Expected a timeout but got %#v", err)
+	}
+	timeout, ok := exception.PayloadOf[exception.Timeout](err)
+	if !ok || timeout.GoroutineID == 0 || timeout.Elapsed < 10*time.Millisecond {
+		t.Errorf("Expected the timeout details but got %#v", timeout)
+	}
+	found := false
+	for _, frame := range err.(exception.Exception).GetStackTrace() {
+		found = found || frame.Function == "github.com/thanhminhmr/go-exception_test.stuck"
+	}
+	if !found {
+		t.Errorf("Expected the stack trace of the stuck goroutine but got %v", err.(exception.Exception).GetStackTrace())
+	}
+}
+
+func TestWatchResult(t *testing.T) {
+	err := exception.Watch(context.Background(), time.Second, func(ctx context.Context) error {
+		return UsageError
+	})
+	if err != UsageError {
+		t.Errorf("Expected the result of the function but got %#v", err)
+	}
+	err = exception.Watch(context.Background(), time.Second, func(ctx context.Context) error {
+		panic("Test")
+	})
+	if !errors.Is(err, exception.PanicError) {
+		t.Errorf("Expected the panic to be recovered but got %#v", err)
+	}
+}
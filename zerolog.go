@@ -9,51 +9,73 @@
 package exception
 
 import (
+	"strings"
+
 	"github.com/rs/zerolog"
 )
 
 // MarshalZerologObject marshall this [Exception] as a zerolog object.
 func (e String) MarshalZerologObject(event *zerolog.Event) {
-	event.Str("error", string(e))
+	policy := GetRedactionPolicy()
+	if policy == nil {
+		event.Str("error", string(e))
+		return
+	}
+	t, m, ok := strings.Cut(string(e), separator)
+	if !ok {
+		event.Str("error", string(e))
+	} else if policy.IsMasked("message") {
+		event.Str("error", t+separator+policy.mask())
+	} else {
+		event.Str("error", t+separator+policy.RedactMessage(m))
+	}
 }
 
 func (e fullException) MarshalZerologObject(event *zerolog.Event) {
+	policy := GetRedactionPolicy()
 	event.Str("error", e.Type)
-	if e.Message != "" {
-		event.Str("message", e.Message)
+	if e.ID != "" {
+		event.Str("id", e.ID)
 	}
-	switch len(e.Cause) {
-	case 0: // skip
-	case 1:
-		event.AnErr("cause", e.Cause[0])
-	default:
-		event.Errs("cause", e.Cause)
+	if !e.Time.IsZero() {
+		event.Time("created_at", e.Time)
 	}
-	switch len(e.Suppressed) {
-	case 0: // skip
-	case 1:
-		event.AnErr("suppressed", e.Suppressed[0])
-	default:
-		event.Errs("suppressed", e.Suppressed)
+	if e.Message != "" {
+		zerologMessage(event, policy, "message", e.Message)
 	}
+	zerologErrors(event, policy, "cause", e.Causes)
+	zerologErrors(event, policy, "suppressed", e.Suppressed)
 	if e.Recovered != nil {
-		event.Any("recovered", e.Recovered)
+		zerologRecovered(event, policy, "recovered", e.Recovered)
+	}
+	if e.Stack != nil {
+		event.Any("stack_trace", e.Stack)
 	}
-	if e.StackTrace != nil {
-		event.Any("stack_trace", e.StackTrace)
+	if e.Goroutines != nil {
+		event.Array("goroutines", goroutineArray(e.Goroutines))
 	}
 }
 
-func (e multipleErrors) MarshalZerologObject(event *zerolog.Event) {
-	switch len(e) {
-	case 0: // skip
-	case 1:
-		event.AnErr("cause", e[0])
-	default:
-		event.Errs("cause", e)
+// MarshalZerologObject marshall this [Exception] as a zerolog object.
+func (b *Base) MarshalZerologObject(event *zerolog.Event) {
+	b.exception.MarshalZerologObject(event)
+}
+
+// MarshalZerologObject marshall this [Exception] as a zerolog object, including
+// its payload.
+func (e *Typed[T]) MarshalZerologObject(event *zerolog.Event) {
+	e.Base.MarshalZerologObject(event)
+	if policy := GetRedactionPolicy(); policy.IsMasked("payload") {
+		event.Str("payload", policy.mask())
+	} else {
+		event.Any("payload", e.Payload)
 	}
 }
 
+func (e multipleErrors) MarshalZerologObject(event *zerolog.Event) {
+	zerologErrors(event, GetRedactionPolicy(), "cause", e)
+}
+
 // MarshalZerologObject marshall this [StackFrame] as a zerolog object.
 func (f StackFrame) MarshalZerologObject(event *zerolog.Event) {
 	event.Str("function", f.Function).Str("file", f.File).Int("line", f.Line)
@@ -65,3 +87,90 @@ func (s StackFrames) MarshalZerologArray(array *zerolog.Array) {
 		array.Object(frame)
 	}
 }
+
+// MarshalZerologObject marshall this [Goroutine] as a zerolog object.
+func (g Goroutine) MarshalZerologObject(event *zerolog.Event) {
+	event.Int("id", g.ID).Str("state", g.State)
+	if g.Wait != 0 {
+		event.Dur("wait", g.Wait)
+	}
+	event.Array("stack_trace", g.StackTrace)
+	if g.CreatedBy.Function != "" {
+		event.Object("created_by", g.CreatedBy).Int("creator_id", g.CreatorID)
+	}
+	if g.Exception != nil {
+		event.AnErr("exception", g.Exception)
+	}
+}
+
+// goroutineArray marshall a goroutine snapshot as a zerolog array.
+type goroutineArray []Goroutine
+
+func (a goroutineArray) MarshalZerologArray(array *zerolog.Array) {
+	for _, goroutine := range a {
+		array.Object(goroutine)
+	}
+}
+
+// ========================================
+
+func zerologMessage(event *zerolog.Event, policy *RedactionPolicy, key string, message string) {
+	if policy.IsMasked(key) {
+		event.Str(key, policy.mask())
+	} else {
+		event.Str(key, policy.RedactMessage(message))
+	}
+}
+
+func zerologErrors(event *zerolog.Event, policy *RedactionPolicy, key string, errors []error) {
+	switch {
+	case len(errors) == 0: // skip
+	case policy.IsMasked(key):
+		event.Str(key, policy.mask())
+	case len(errors) == 1:
+		event.AnErr(key, zerologError(policy, errors[0]))
+	default:
+		marshalled := make([]error, len(errors))
+		for i, err := range errors {
+			marshalled[i] = zerologError(policy, err)
+		}
+		event.Errs(key, marshalled)
+	}
+}
+
+// zerologError returns the error to marshal: the redacted error, together with
+// its stack trace when it is a foreign error carrying one.
+func zerologError(policy *RedactionPolicy, err error) error {
+	if _, ok := err.(Exception); ok {
+		return err
+	}
+	if trace := StackTraceOf(err); trace != nil {
+		return foreignError{err: err, trace: trace, policy: policy}
+	}
+	return policy.RedactError(err)
+}
+
+// foreignError is an error from another library carrying a stack trace.
+type foreignError struct {
+	err    error
+	trace  StackFrames
+	policy *RedactionPolicy
+}
+
+func (e foreignError) Error() string {
+	return e.policy.RedactError(e.err).Error()
+}
+
+func (e foreignError) MarshalZerologObject(event *zerolog.Event) {
+	event.Str("error", "")
+	zerologMessage(event, e.policy, "message", e.err.Error())
+	event.Array("stack_trace", e.trace)
+}
+
+func zerologRecovered(event *zerolog.Event, policy *RedactionPolicy, key string, recovered any) {
+	if policy.IsMasked(key) {
+		event.Str(key, policy.mask())
+	} else {
+		event.Any(key, policy.RedactRecovered(recovered))
+	}
+}